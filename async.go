@@ -0,0 +1,185 @@
+package tinylog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/andriiyaremenko/tinylog/formatters"
+)
+
+const defaultQueueSize int = 1024
+
+// Returned by AsyncWriter.Write after AsyncWriter was closed.
+var ErrAsyncWriterClosed = errors.New("async writer is closed")
+
+// Defines what AsyncWriter does with log entry when its queue is full.
+type OverflowPolicy int
+
+const (
+	// Write waits until there is space in queue.
+	Block OverflowPolicy = iota
+	// Log entry being written is dropped.
+	DropNewest
+	// Oldest log entry in queue is dropped to make space for the one being written.
+	DropOldest
+)
+
+// Configuration of AsyncWriter.
+type AsyncOptions struct {
+	// Maximum number of log entries waiting to be written.
+	// 0 = 1024.
+	QueueSize int
+	// What to do with log entry when queue is full.
+	Overflow OverflowPolicy
+}
+
+// Wraps out of wrapped Destination with AsyncWriter.
+// Log entries are still formatted by the caller, but written to out by background goroutine.
+func AsyncDestination(wrapped Destination, opts AsyncOptions) Destination {
+	dest := wrapped()
+	out := NewAsyncWriter(dest.out, opts)
+
+	// formatter stays bound to the original out.
+	return func() *destination {
+		return &destination{out: out, formatter: dest.formatter, level: dest.level, sampler: dest.sampler}
+	}
+}
+
+// Returns new instance of AsyncWriter and starts its background goroutine.
+func NewAsyncWriter(out io.Writer, opts AsyncOptions) *AsyncWriter {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultQueueSize
+	}
+
+	aw := &AsyncWriter{out: out, opts: opts, done: make(chan struct{})}
+	aw.cond = sync.NewCond(&aw.mu)
+
+	go aw.run()
+
+	return aw
+}
+
+// AsyncWriter is io.Writer that queues log entries and writes them to out by background goroutine.
+type AsyncWriter struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	out     io.Writer
+	opts    AsyncOptions
+	queue   [][]byte
+	writing bool
+	closed  bool
+	done    chan struct{}
+	dropped atomic.Uint64
+}
+
+// Queues copy of p to be written to out.
+// Never returns error for dropped log entry.
+func (aw *AsyncWriter) Write(p []byte) (int, error) {
+	aw.mu.Lock()
+	defer aw.mu.Unlock()
+
+	for !aw.closed && len(aw.queue) >= aw.opts.QueueSize {
+		switch aw.opts.Overflow {
+		case DropNewest:
+			aw.dropped.Add(1)
+			return len(p), nil
+		case DropOldest:
+			aw.queue[0] = nil
+			aw.queue = aw.queue[1:]
+			aw.dropped.Add(1)
+		default:
+			aw.cond.Wait()
+		}
+	}
+
+	if aw.closed {
+		return 0, ErrAsyncWriterClosed
+	}
+
+	entry := make([]byte, len(p))
+	copy(entry, p)
+
+	aw.queue = append(aw.queue, entry)
+	aw.cond.Broadcast()
+
+	return len(p), nil
+}
+
+// Waits until all queued log entries are written to out and flushes out if it implements Flusher.
+func (aw *AsyncWriter) Flush() error {
+	aw.mu.Lock()
+	for len(aw.queue) > 0 || aw.writing {
+		aw.cond.Wait()
+	}
+	aw.mu.Unlock()
+
+	return flushWriter(aw.out)
+}
+
+// Writes all queued log entries to out, stops background goroutine
+// and closes out if it implements io.Closer (os.Stdout and os.Stderr are never closed).
+func (aw *AsyncWriter) Close() error {
+	aw.mu.Lock()
+	alreadyClosed := aw.closed
+	aw.closed = true
+	aw.cond.Broadcast()
+	aw.mu.Unlock()
+
+	<-aw.done
+
+	if alreadyClosed {
+		return nil
+	}
+
+	if err := flushWriter(aw.out); err != nil {
+		return err
+	}
+
+	return closeWriter(aw.out)
+}
+
+// Returns number of log entries dropped because queue was full.
+func (aw *AsyncWriter) Dropped() uint64 {
+	return aw.dropped.Load()
+}
+
+func (aw *AsyncWriter) run() {
+	defer close(aw.done)
+
+	for {
+		aw.mu.Lock()
+		for len(aw.queue) == 0 && !aw.closed {
+			aw.cond.Wait()
+		}
+
+		if len(aw.queue) == 0 {
+			aw.mu.Unlock()
+			return
+		}
+
+		// entry is taken from queue only when it is written,
+		// so there are never more than QueueSize entries waiting and DropOldest can drop any of them.
+		entry := aw.queue[0]
+		aw.queue[0] = nil
+		aw.queue = aw.queue[1:]
+		aw.writing = true
+		aw.cond.Broadcast()
+		aw.mu.Unlock()
+
+		if _, err := aw.out.Write(entry); err != nil {
+			fmt.Println(
+				formatters.PaintText(
+					formatters.ANSIColorRed,
+					fmt.Sprintf("failed to write log to %T: %s", aw.out, err)))
+		}
+
+		aw.mu.Lock()
+		aw.writing = false
+		aw.cond.Broadcast()
+		aw.mu.Unlock()
+	}
+}
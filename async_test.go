@@ -0,0 +1,187 @@
+package tinylog
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/andriiyaremenko/tinylog/formatters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsyncWriter(t *testing.T) {
+	t.Run("AsyncWriter writes entries in background", testAsyncWriterWrites)
+	t.Run("AsyncWriter with DropNewest drops entry being written", testAsyncWriterDropNewest)
+	t.Run("AsyncWriter with DropOldest drops oldest queued entry", testAsyncWriterDropOldest)
+	t.Run("AsyncWriter with Block waits for space in queue", testAsyncWriterBlock)
+	t.Run("AsyncWriter keeps no more than QueueSize entries waiting", testAsyncWriterQueueSize)
+	t.Run("AsyncWriter returns error after Close", testAsyncWriterClosed)
+	t.Run("AsyncDestination can be used by Logger", testAsyncDestination)
+}
+
+// gatedWriter blocks every Write until gate is closed.
+type gatedWriter struct {
+	cw      *concurrentWriter
+	gate    chan struct{}
+	started chan struct{}
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{
+		cw:      &concurrentWriter{b: new(bytes.Buffer)},
+		gate:    make(chan struct{}),
+		started: make(chan struct{}, 1)}
+}
+
+func (gw *gatedWriter) Write(p []byte) (int, error) {
+	select {
+	case gw.started <- struct{}{}:
+	default:
+	}
+
+	<-gw.gate
+	return gw.cw.Write(p)
+}
+
+// steppedWriter blocks every Write until it is released.
+type steppedWriter struct {
+	cw      *concurrentWriter
+	started chan struct{}
+	release chan struct{}
+}
+
+func (sw *steppedWriter) Write(p []byte) (int, error) {
+	sw.started <- struct{}{}
+	<-sw.release
+	return sw.cw.Write(p)
+}
+
+func testAsyncWriterWrites(t *testing.T) {
+	assert := assert.New(t)
+	cw := &concurrentWriter{b: new(bytes.Buffer)}
+	aw := NewAsyncWriter(cw, AsyncOptions{})
+	p := []byte("first\n")
+
+	aw.Write(p)
+	copy(p, "reused")
+	aw.Write([]byte("second\n"))
+
+	assert.NoError(aw.Flush())
+	assert.Equal("first\nsecond\n", cw.String(), "all entries should be written after Flush")
+	assert.NoError(aw.Close())
+}
+
+func writeWhileBlocked(aw *AsyncWriter, gw *gatedWriter, entries ...string) {
+	aw.Write([]byte("1"))
+	<-gw.started
+
+	for _, entry := range entries {
+		aw.Write([]byte(entry))
+	}
+}
+
+func testAsyncWriterDropNewest(t *testing.T) {
+	assert := assert.New(t)
+	gw := newGatedWriter()
+	aw := NewAsyncWriter(gw, AsyncOptions{QueueSize: 1, Overflow: DropNewest})
+
+	writeWhileBlocked(aw, gw, "2", "3")
+	close(gw.gate)
+
+	assert.NoError(aw.Close())
+	assert.Equal("12", gw.cw.String(), "newest entry should be dropped")
+	assert.Equal(uint64(1), aw.Dropped(), "dropped entry should be counted")
+}
+
+func testAsyncWriterDropOldest(t *testing.T) {
+	assert := assert.New(t)
+	gw := newGatedWriter()
+	aw := NewAsyncWriter(gw, AsyncOptions{QueueSize: 1, Overflow: DropOldest})
+
+	writeWhileBlocked(aw, gw, "2", "3")
+	close(gw.gate)
+
+	assert.NoError(aw.Close())
+	assert.Equal("13", gw.cw.String(), "oldest queued entry should be dropped")
+	assert.Equal(uint64(1), aw.Dropped(), "dropped entry should be counted")
+}
+
+func testAsyncWriterBlock(t *testing.T) {
+	assert := assert.New(t)
+	gw := newGatedWriter()
+	aw := NewAsyncWriter(gw, AsyncOptions{QueueSize: 1, Overflow: Block})
+
+	writeWhileBlocked(aw, gw, "2")
+
+	written := make(chan struct{})
+	go func() {
+		aw.Write([]byte("3"))
+		close(written)
+	}()
+
+	select {
+	case <-written:
+		assert.Fail("Write should wait for space in queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(gw.gate)
+	<-written
+
+	assert.NoError(aw.Close())
+	assert.Equal("123", gw.cw.String(), "no entry should be dropped")
+	assert.Zero(aw.Dropped(), "no entry should be dropped")
+}
+
+func testAsyncWriterQueueSize(t *testing.T) {
+	assert := assert.New(t)
+	sw := &steppedWriter{
+		cw:      &concurrentWriter{b: new(bytes.Buffer)},
+		started: make(chan struct{}, 8),
+		release: make(chan struct{})}
+	aw := NewAsyncWriter(sw, AsyncOptions{QueueSize: 2, Overflow: DropOldest})
+
+	aw.Write([]byte("1"))
+	<-sw.started
+	aw.Write([]byte("2"))
+	aw.Write([]byte("3"))
+
+	sw.release <- struct{}{}
+	<-sw.started
+
+	// "2" is being written, "3" is waiting.
+	for _, entry := range []string{"4", "5", "6"} {
+		aw.Write([]byte(entry))
+	}
+
+	close(sw.release)
+
+	assert.NoError(aw.Close())
+	assert.Equal("1256", sw.cw.String(), "oldest waiting entries should be dropped")
+	assert.Equal(uint64(2), aw.Dropped(), "dropped entries should be counted")
+}
+
+func testAsyncWriterClosed(t *testing.T) {
+	assert := assert.New(t)
+	aw := NewAsyncWriter(new(bytes.Buffer), AsyncOptions{})
+
+	assert.NoError(aw.Close())
+	assert.NoError(aw.Close(), "Close should be safe to call twice")
+
+	_, err := aw.Write([]byte("late"))
+
+	assert.Equal(ErrAsyncWriterClosed, err, "Write should fail after Close")
+}
+
+func testAsyncDestination(t *testing.T) {
+	assert := assert.New(t)
+	cw := &concurrentWriter{b: new(bytes.Buffer)}
+	dest := AsyncDestination(DestinationFunc(cw, formatters.Default(), Info), AsyncOptions{})
+	l := NewLogger(dest)
+
+	l.Println(Info, "async")
+	dest().out.(*AsyncWriter).Flush()
+
+	assert.Contains(cw.String(), "async", "message should be written")
+	assert.Equal(dest().ID(), dest().ID(), "AsyncDestination should wrap out once")
+}
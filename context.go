@@ -0,0 +1,34 @@
+package tinylog
+
+import "context"
+
+type loggerContextKey struct{}
+
+// Returns fields extracted from context values, e.g. request ID or tenant ID.
+type ContextExtractor func(ctx context.Context) []Field
+
+// ContextExtractors used if none were configured.
+var defaultContextExtractors = []ContextExtractor{TraceExtractor}
+
+// Returns copy of ctx that carries l.
+// l is available from ctx and every context derived from it with FromContext.
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, l)
+}
+
+// Returns Logger carried by ctx.
+// Returns false if ctx carries no Logger.
+func FromContext(ctx context.Context) (Logger, bool) {
+	l, ok := ctx.Value(loggerContextKey{}).(Logger)
+	return l, ok
+}
+
+// Returns fields of every extractor in order.
+func extractFields(ctx context.Context, extractors []ContextExtractor) []Field {
+	var fields []Field
+	for _, extract := range extractors {
+		fields = append(fields, extract(ctx)...)
+	}
+
+	return fields
+}
@@ -0,0 +1,159 @@
+package tinylog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/andriiyaremenko/tinylog/formatters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext(t *testing.T) {
+	t.Run("FromContext returns Logger from derived contexts", testFromContext)
+	t.Run("FromContext returns false if there is no Logger", testFromContextWithoutLogger)
+	t.Run("GetLogger falls back to Logger carried by context", testGetLoggerFromContext)
+	t.Run("GetLogger with Destinations does not fall back to Logger carried by context",
+		testGetLoggerWithDestinationsIgnoresContext)
+	t.Run("LoggerFactory levels do not change levels of Logger carried by context",
+		testGetLoggerFromContextOwnLevels)
+	t.Run("ContextExtractors add fields extracted from context", testContextExtractors)
+	t.Run("SlogHandler ContextExtractors add fields extracted from record context", testSlogContextExtractors)
+}
+
+type requestIDKey struct{}
+
+func requestIDExtractor(ctx context.Context) []Field {
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return []Field{String("request_id", id)}
+	}
+
+	return nil
+}
+
+func testFromContext(t *testing.T) {
+	assert := assert.New(t)
+	l := NewLogger(DestinationFunc(new(bytes.Buffer), formatters.JSONFormatter, Info))
+	ctx := NewContext(context.TODO(), l)
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	found, ok := FromContext(context.WithValue(ctx, struct{}{}, "value"))
+
+	assert.True(ok, "Logger should be found")
+	assert.Equal(l, found, "Logger should be the same")
+}
+
+func testFromContextWithoutLogger(t *testing.T) {
+	assert := assert.New(t)
+	l, ok := FromContext(context.TODO())
+
+	assert.False(ok, "Logger should not be found")
+	assert.Nil(l, "Logger should be nil")
+}
+
+func testGetLoggerFromContext(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	lf := NewLoggerFactory(DestinationFunc(b, formatters.JSONFormatter, Info))
+	parent := context.TODO()
+	l := lf.GetLogger(parent).With(String("request", "42"))
+
+	ctx, cancel := context.WithTimeout(NewContext(parent, l), time.Minute)
+	defer cancel()
+
+	assert.Equal(l, lf.GetLogger(ctx), "Logger carried by context should be used")
+
+	lf.Named("db").GetLogger(ctx).Println(Info, "query")
+	lf.Named("db").GetLogger(ctx).Println(Info, "query")
+
+	dec := json.NewDecoder(b)
+	for i := 0; i < 2; i++ {
+		m := decodeLog(assert, dec)
+
+		assert.Equal("db", m.Logger, "name of LoggerFactory should be added")
+		assert.Equal("42", m.Tags["request"], "fields of Logger carried by context should be printed")
+	}
+}
+
+func testGetLoggerWithDestinationsIgnoresContext(t *testing.T) {
+	assert := assert.New(t)
+	lf, _ := getLoggerFactory()
+	l := NewLogger(DestinationFunc(new(bytes.Buffer), formatters.JSONFormatter, Info))
+	ctx := NewContext(context.TODO(), l)
+
+	assert.NotEqual(l, lf.GetLogger(ctx, AllDestinations(lf)...), "new Logger should be returned")
+}
+
+func testGetLoggerFromContextOwnLevels(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	lf := NewLoggerFactory(DestinationFunc(b, formatters.JSONFormatter, Info))
+	lf.Named("db").SetLogLevel(Warn)
+
+	parent := context.TODO()
+	l := lf.GetLogger(parent)
+	ctx, cancel := context.WithTimeout(NewContext(parent, l), time.Minute)
+	defer cancel()
+
+	db := lf.Named("db").GetLogger(ctx)
+	http := lf.Named("http").GetLogger(ctx)
+	lf.Named("http").SetLogLevel(Trace)
+
+	l.Println(Trace, "root")
+	db.Println(Info, "db")
+	http.Println(Trace, "http")
+	l.Println(Info, "root")
+
+	dec := json.NewDecoder(b)
+	for _, message := range []string{"http", "root"} {
+		assert.Equal(message, decodeLog(assert, dec).Message, "levels of LoggerFactory names should be applied")
+	}
+
+	assert.False(dec.More(), "levels of Logger carried by context should not change")
+}
+
+func testContextExtractors(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	lf := NewLoggerFactoryWithOptions(
+		FactoryOptions{ContextExtractors: []ContextExtractor{requestIDExtractor}},
+		DestinationFunc(b, formatters.JSONFormatter, Info))
+	ctx := context.WithValue(context.TODO(), requestIDKey{}, "42")
+
+	lf.GetLogger(ctx).Println(Info, "request")
+	lf.Named("db").GetLogger(ctx).Println(Info, "query")
+	lf.GetLogger(NewContext(context.TODO(), NewLogger(DestinationFunc(b, formatters.JSONFormatter, Info)))).
+		Println(Info, "no request")
+
+	dec := json.NewDecoder(b)
+	for _, message := range []string{"request", "query"} {
+		m := decodeLog(assert, dec)
+
+		assert.Equal(message, m.Message)
+		assert.Equal("42", m.Tags["request_id"], "extracted field should be printed")
+	}
+
+	m := decodeLog(assert, dec)
+
+	assert.Equal("no request", m.Message)
+	assert.Empty(m.Tags, "there should be no extracted fields")
+}
+
+func testSlogContextExtractors(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	h := NewSlogHandler(DestinationFunc(b, formatters.JSONFormatter, Info)).
+		WithContextExtractors(requestIDExtractor)
+	ctx := context.WithValue(context.TODO(), requestIDKey{}, "42")
+
+	slog.New(h).WithGroup("user").InfoContext(ctx, "request", "id", 7)
+
+	m := decodeLog(assert, json.NewDecoder(b))
+
+	assert.Equal("42", m.Tags["request_id"], "extracted field should be printed")
+	assert.Equal(map[string]interface{}{"id": float64(7)}, m.Tags["user"], "attributes should be printed")
+}
@@ -1,9 +1,11 @@
 package tinylog
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"sync"
 
 	"github.com/andriiyaremenko/tinylog/formatters"
 )
@@ -13,22 +15,106 @@ func AllDestinations(factory LoggerFactory) []Destination {
 	return factory.Destinations()
 }
 
+// Implemented by Destination out that buffers log entries.
+type Flusher interface {
+	// Writes all buffered log entries.
+	Flush() error
+}
+
 // Destination constructor function.
+// formatter that implements formatters.WriterFormatter is bound to out.
 func DestinationFunc(out io.Writer, formatter formatters.LogFormatter, level int) Destination {
+	formatter = bindFormatter(formatter, out)
+
 	return func() *destination { return &destination{out: out, formatter: formatter, level: level} }
 }
 
 // Destination based on os.Stderr as out and formatters.Default() as formatter.
 func DefaultDestination() *destination {
-	return &destination{out: os.Stderr, formatter: formatters.Default(), level: Info}
+	return &destination{out: os.Stderr, formatter: defaultStderrFormatter, level: Info}
+}
+
+var defaultStderrFormatter = bindFormatter(formatters.Default(), os.Stderr)
+
+func bindFormatter(formatter formatters.LogFormatter, out io.Writer) formatters.LogFormatter {
+	if f, ok := formatter.(formatters.WriterFormatter); ok {
+		return f.ForWriter(out)
+	}
+
+	return formatter
 }
 
 type destination struct {
 	level     int
 	out       io.Writer
 	formatter formatters.LogFormatter
+	// nil = every entry is written.
+	sampler *sampler
 }
 
 func (d *destination) ID() string {
-	return fmt.Sprintf("%T->%T<%p>", d.formatter, d.out, d.out)
+	return fmt.Sprintf("%T->%s", d.formatter, d.outID())
+}
+
+func (d *destination) outID() string {
+	return fmt.Sprintf("%T<%p>", d.out, d.out)
+}
+
+// destinationSet is shared between Logger and Loggers derived from it.
+type destinationSet struct {
+	mu sync.RWMutex
+
+	list []*destination
+}
+
+// Flushes or closes outs of destinations, each out only once.
+// Summaries of entries dropped by samplers of destinations are written first.
+func closeDestinations(destinations []*destination, closeOut bool) error {
+	flushDestinationSamplers(destinations)
+
+	var errs []error
+	done := make(map[string]struct{})
+
+	for _, dest := range destinations {
+		if _, ok := done[dest.outID()]; ok {
+			continue
+		}
+
+		done[dest.outID()] = struct{}{}
+
+		if err := flushWriter(dest.out); err != nil {
+			errs = append(errs, fmt.Errorf("failed to flush destination %s: %w", dest.ID(), err))
+		}
+
+		if !closeOut {
+			continue
+		}
+
+		if err := closeWriter(dest.out); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close destination %s: %w", dest.ID(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func flushWriter(w io.Writer) error {
+	if f, ok := w.(Flusher); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
+
+// os.Stdout and os.Stderr are never closed.
+func closeWriter(w io.Writer) error {
+	if w == os.Stdout || w == os.Stderr {
+		return nil
+	}
+
+	if c, ok := w.(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
 }
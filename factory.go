@@ -3,11 +3,30 @@ package tinylog
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
+
+	"github.com/andriiyaremenko/tinylog/formatters"
 )
 
+// Configuration of LoggerFactory.
+type FactoryOptions struct {
+	// Run in order every time Logger is bound to context, fields they return are added to the Logger.
+	// nil = TraceExtractor only, include TraceExtractor to keep trace ID and span ID when providing your own.
+	ContextExtractors []ContextExtractor
+}
+
 // Returns new instance of LoggerFactory based on out and formatter.
 func NewLoggerFactory(destinations ...Destination) LoggerFactory {
+	return NewLoggerFactoryWithOptions(FactoryOptions{}, destinations...)
+}
+
+// Returns new instance of LoggerFactory configured with opts.
+func NewLoggerFactoryWithOptions(opts FactoryOptions, destinations ...Destination) LoggerFactory {
+	if opts.ContextExtractors == nil {
+		opts.ContextExtractors = defaultContextExtractors
+	}
+
 	if len(destinations) == 0 {
 		panic("no destination was provided for LoggerFactory")
 	}
@@ -21,8 +40,9 @@ func NewLoggerFactory(destinations ...Destination) LoggerFactory {
 	}
 
 	return &tinyLoggerFactory{
-		loggers:      make(map[context.Context]Logger),
-		destinations: destinations}
+		registry:     &loggerRegistry{contexts: make(map[context.Context]*contextLoggers)},
+		destinations: destinations,
+		extractors:   opts.ContextExtractors}
 }
 
 // Returns new instance of LoggerFactory with DefaultDestination.
@@ -30,50 +50,272 @@ func DefaultLoggerFactory() LoggerFactory {
 	return NewLoggerFactory(DefaultDestination)
 }
 
-type tinyLoggerFactory struct {
+// Verbosity level set for Loggers with name or names nested in it.
+type levelRule struct {
+	name         string
+	level        int
+	destinations []Destination
+	ids          map[string]struct{}
+}
+
+func (lr *levelRule) appliesTo(name string) bool {
+	return isNameNested(name, lr.name)
+}
+
+// Reports if rule overrides everything other rule sets.
+func (lr *levelRule) overrides(other *levelRule) bool {
+	if !isNameNested(other.name, lr.name) {
+		return false
+	}
+
+	if len(lr.ids) == 0 {
+		return true
+	}
+
+	if len(other.ids) == 0 {
+		return false
+	}
+
+	for id := range other.ids {
+		if _, ok := lr.ids[id]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// loggerRegistry is shared between LoggerFactory and LoggerFactories derived from it by Named.
+type loggerRegistry struct {
 	mu sync.Mutex
 
-	loggers      map[context.Context]Logger
+	contexts map[context.Context]*contextLoggers
+	levels   []*levelRule
+	live     int
+	created  uint64
+	released uint64
+}
+
+// Loggers bound to one context by name.
+type contextLoggers struct {
+	loggers map[string]Logger
+	// stops context.AfterFunc that releases loggers.
+	stop func() bool
+}
+
+// Removes loggers bound to ctx.
+// Must be called with mu held.
+func (lr *loggerRegistry) release(ctx context.Context) {
+	cl, ok := lr.contexts[ctx]
+	if !ok {
+		return
+	}
+
+	delete(lr.contexts, ctx)
+	cl.stop()
+
+	lr.live -= len(cl.loggers)
+	lr.released += uint64(len(cl.loggers))
+}
+
+type tinyLoggerFactory struct {
+	name         string
+	registry     *loggerRegistry
 	destinations []Destination
+	extractors   []ContextExtractor
 }
 
 func (tlf *tinyLoggerFactory) Destinations() []Destination {
 	return tlf.destinations
 }
 
+func (tlf *tinyLoggerFactory) Named(name string) LoggerFactory {
+	if tlf.name != "" {
+		name = tlf.name + "." + name
+	}
+
+	return &tinyLoggerFactory{
+		name:         name,
+		registry:     tlf.registry,
+		destinations: tlf.destinations,
+		extractors:   tlf.extractors}
+}
+
 func (tlf *tinyLoggerFactory) GetLogger(ctx context.Context, destinations ...Destination) Logger {
-	tlf.mu.Lock()
-	defer tlf.mu.Unlock()
-	_, ok := tlf.loggers[ctx]
+	registry := tlf.registry
 
-	if !ok {
-		go func() {
-			<-ctx.Done()
-			tlf.mu.Lock()
-			delete(tlf.loggers, ctx)
-			tlf.mu.Unlock()
-		}()
+	registry.mu.Lock()
+	if cl, ok := registry.contexts[ctx]; ok {
+		if l, ok := cl.loggers[tlf.name]; ok {
+			registry.mu.Unlock()
+			return l
+		}
 	}
+	registry.mu.Unlock()
+
+	// ContextExtractors may log through LoggerFactory, so Logger is built without lock held.
+	l := tlf.newLogger(ctx, destinations)
 
-	_, ok = tlf.loggers[ctx]
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
 
+	cl, ok := registry.contexts[ctx]
 	if !ok {
-		if len(destinations) == 0 {
-			destinations = AllDestinations(tlf)
+		cl = &contextLoggers{loggers: make(map[string]Logger)}
+		// context.AfterFunc does not start goroutine until ctx is done
+		// and never runs f for contexts that can not be cancelled.
+		cl.stop = context.AfterFunc(ctx, func() {
+			registry.mu.Lock()
+			registry.release(ctx)
+			registry.mu.Unlock()
+		})
+
+		registry.contexts[ctx] = cl
+	}
+
+	// Logger could be bound to ctx by another goroutine meanwhile.
+	if bound, ok := cl.loggers[tlf.name]; ok {
+		return bound
+	}
+
+	for _, rule := range registry.levels {
+		if rule.appliesTo(tlf.name) {
+			l.SetLogLevel(rule.level, rule.destinations...)
 		}
+	}
 
-		l := NewLogger(destinations...)
-		tlf.loggers[ctx] = l
+	cl.loggers[tlf.name] = l
+	registry.live++
+	registry.created++
+
+	return l
+}
+
+// Returns Logger for ctx tagged with LoggerFactory name and fields ContextExtractors return for ctx.
+func (tlf *tinyLoggerFactory) newLogger(ctx context.Context, destinations []Destination) Logger {
+	l, found := FromContext(ctx)
+
+	if found && len(destinations) == 0 {
+		fields := extractFields(ctx, tlf.extractors)
+		if tlf.name != "" {
+			fields = append(fields, String(formatters.LoggerKey, tlf.name))
+		}
+
+		// levels are set on copy of destinations,
+		// so they do not change for Logger carried by ctx and Loggers derived from it.
+		if tl, ok := l.(*tinyLogger); ok {
+			return tl.withOwnLevels(fields...)
+		}
+
+		if len(fields) > 0 {
+			return l.With(fields...)
+		}
+
+		return l
+	}
+
+	if len(destinations) == 0 {
+		destinations = AllDestinations(tlf)
+	}
+
+	l = NewLogger(destinations...)
+
+	if tlf.name != "" {
+		l.AddFields(String(formatters.LoggerKey, tlf.name))
 	}
 
-	return tlf.loggers[ctx]
+	l.AddFields(extractFields(ctx, tlf.extractors)...)
+
+	return l
+}
+
+func (tlf *tinyLoggerFactory) Release(ctx context.Context) {
+	tlf.registry.mu.Lock()
+	tlf.registry.release(ctx)
+	tlf.registry.mu.Unlock()
+}
+
+func (tlf *tinyLoggerFactory) Stats() FactoryStats {
+	tlf.registry.mu.Lock()
+	defer tlf.registry.mu.Unlock()
+
+	return FactoryStats{
+		LiveLoggers:  tlf.registry.live,
+		LiveContexts: len(tlf.registry.contexts),
+		Created:      tlf.registry.created,
+		Released:     tlf.registry.released,
+	}
 }
 
 func (tlf *tinyLoggerFactory) SetLogLevel(level int, destinations ...Destination) {
-	tlf.mu.Lock()
-	defer tlf.mu.Unlock()
+	rule := &levelRule{name: tlf.name, level: level, destinations: destinations}
+
+	if len(destinations) > 0 {
+		rule.ids = make(map[string]struct{})
+		for _, dest := range destinations {
+			rule.ids[dest().ID()] = struct{}{}
+		}
+	}
+
+	registry := tlf.registry
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	levels := registry.levels[:0]
+	for _, other := range registry.levels {
+		if !rule.overrides(other) {
+			levels = append(levels, other)
+		}
+	}
+
+	registry.levels = append(levels, rule)
+
+	for _, cl := range registry.contexts {
+		for name, l := range cl.loggers {
+			if rule.appliesTo(name) {
+				l.SetLogLevel(level, destinations...)
+			}
+		}
+	}
+}
 
-	for _, l := range tlf.loggers {
-		l.SetLogLevel(level, destinations...)
+func (tlf *tinyLoggerFactory) Sync() error {
+	return closeDestinations(tlf.allDestinations(), false)
+}
+
+func (tlf *tinyLoggerFactory) Close() error {
+	return closeDestinations(tlf.allDestinations(), true)
+}
+
+// Returns LoggerFactory Destinations and Destinations of every Logger it manages.
+func (tlf *tinyLoggerFactory) allDestinations() []*destination {
+	var all []*destination
+	for _, destFunc := range tlf.destinations {
+		all = append(all, destFunc())
+	}
+
+	tlf.registry.mu.Lock()
+	defer tlf.registry.mu.Unlock()
+
+	for _, cl := range tlf.registry.contexts {
+		for _, l := range cl.loggers {
+			tl, ok := l.(*tinyLogger)
+			if !ok {
+				continue
+			}
+
+			tl.destinations.mu.RLock()
+			all = append(all, tl.destinations.list...)
+			tl.destinations.mu.RUnlock()
+		}
 	}
+
+	return all
+}
+
+// Reports if name equals parent or is nested in it.
+// Every name is nested in empty parent.
+func isNameNested(name, parent string) bool {
+	return parent == "" || name == parent || strings.HasPrefix(name, parent+".")
 }
@@ -3,7 +3,11 @@ package tinylog
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"runtime"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/andriiyaremenko/tinylog/formatters"
 	"github.com/stretchr/testify/assert"
@@ -16,6 +20,13 @@ func TestLoggerFactory(t *testing.T) {
 	t.Run("SetLogLevel sets log level for all Logger instances", testLoggersRespectLogLevel)
 	t.Run("SetLogLevel sets log level for all Logger instances for particular Destination",
 		testLoggersRespectLogLevelForParticularDestination)
+	t.Run("Named returns LoggerFactory of Loggers with dotted names", testNamed)
+	t.Run("SetLogLevel of named LoggerFactory sets log level for nested names only", testNamedLogLevel)
+	t.Run("Close closes Destinations of LoggerFactory and its Loggers", testFactoryClose)
+	t.Run("Loggers are released when context is done", testReleasedWhenContextDone)
+	t.Run("Release releases Loggers bound to context", testRelease)
+	t.Run("GetLogger does not start goroutine per context", testGetLoggerGoroutines)
+	t.Run("ContextExtractors can log through LoggerFactory", testExtractorLogsThroughFactory)
 }
 
 func testGetLogger(t *testing.T) {
@@ -123,3 +134,188 @@ func testLoggersRespectLogLevelForParticularDestination(t *testing.T) {
 
 	assert.Empty(result, "no message should be printed for destination2")
 }
+
+func testNamed(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	lf := NewLoggerFactory(DestinationFunc(b, formatters.JSONFormatter, Info))
+	ctx := context.TODO()
+
+	lf.Named("db").Named("pool").GetLogger(ctx).Println(Info, "pool")
+	lf.GetLogger(ctx).Println(Info, "root")
+
+	dec := json.NewDecoder(b)
+	m := new(formatters.Log)
+
+	if err := dec.Decode(m); err != nil {
+		assert.FailNow("got wrong log format")
+	}
+
+	assert.Equal("db.pool", m.Logger, "Logger name should be printed")
+	assert.NotContains(m.Tags, formatters.LoggerKey, "Logger name should not be printed as tag")
+
+	m = new(formatters.Log)
+
+	if err := dec.Decode(m); err != nil {
+		assert.FailNow("got wrong log format")
+	}
+
+	assert.Empty(m.Logger, "root Logger should not have name")
+	assert.NotEqual(lf.GetLogger(ctx), lf.Named("db").GetLogger(ctx),
+		"Loggers should be different for different names")
+}
+
+func testNamedLogLevel(t *testing.T) {
+	assert := assert.New(t)
+	lf, b := getLoggerFactory()
+	ctx := context.TODO()
+	db := lf.Named("db")
+	pool := db.Named("pool").GetLogger(ctx)
+	root := lf.GetLogger(ctx)
+
+	db.SetLogLevel(Trace)
+
+	pool.Println(Trace, "pool trace")
+	root.Println(Trace, "root trace")
+	lf.Named("dbx").GetLogger(ctx).Println(Trace, "dbx trace")
+	db.Named("conn").GetLogger(ctx).Println(Trace, "conn trace")
+
+	result := b.String()
+
+	assert.Contains(result, "pool trace", "message should be printed for db.pool")
+	assert.Contains(result, "conn trace", "message should be printed for Logger created later")
+	assert.NotContains(result, "root trace", "message should not be printed for root")
+	assert.NotContains(result, "dbx trace", "message should not be printed for dbx")
+
+	b.Reset()
+	lf.SetLogLevel(Error)
+
+	pool.Println(Info, "pool info")
+	db.Named("conn").GetLogger(ctx).Println(Info, "conn info")
+
+	assert.Empty(b.String(), "root SetLogLevel should override nested names")
+}
+
+func testFactoryClose(t *testing.T) {
+	assert := assert.New(t)
+	cw1 := new(closableWriter)
+	cw2 := new(closableWriter)
+	lf := NewLoggerFactory(DestinationFunc(cw1, formatters.Default(), Info))
+
+	lf.GetLogger(context.TODO())
+	lf.Named("db").GetLogger(context.TODO(), DestinationFunc(cw2, formatters.Default(), Info))
+
+	assert.NoError(lf.Sync())
+	assert.Equal(1, cw1.flushed, "LoggerFactory Destination should be flushed once")
+	assert.Equal(1, cw2.flushed, "Logger Destination should be flushed once")
+
+	assert.NoError(lf.Close())
+	assert.Equal(1, cw1.closed, "LoggerFactory Destination should be closed once")
+	assert.Equal(1, cw2.closed, "Logger Destination should be closed once")
+}
+
+func testReleasedWhenContextDone(t *testing.T) {
+	assert := assert.New(t)
+	lf, _ := getLoggerFactory()
+	ctx, cancel := context.WithCancel(context.TODO())
+
+	lf.GetLogger(ctx)
+	lf.Named("db").GetLogger(ctx)
+
+	assert.Equal(FactoryStats{LiveLoggers: 2, LiveContexts: 1, Created: 2}, lf.Stats(),
+		"Loggers should be counted")
+
+	cancel()
+
+	assert.Eventually(func() bool { return lf.Stats().LiveLoggers == 0 }, time.Second, time.Millisecond,
+		"Loggers should be released")
+	assert.Equal(FactoryStats{Created: 2, Released: 2}, lf.Stats(), "Loggers should be released")
+}
+
+func testRelease(t *testing.T) {
+	assert := assert.New(t)
+	lf, _ := getLoggerFactory()
+	ctx := context.Background()
+	l := lf.GetLogger(ctx)
+	l.AddTag("tag", "released")
+
+	lf.Release(ctx)
+
+	assert.Equal(FactoryStats{Created: 1, Released: 1}, lf.Stats(), "Logger should be released")
+	assert.NotEqual(l, lf.GetLogger(ctx), "new Logger should be returned after Release")
+
+	lf.Release(ctx)
+	lf.Release(ctx)
+
+	assert.Equal(FactoryStats{Created: 2, Released: 2}, lf.Stats(), "Logger should be released once")
+}
+
+func testGetLoggerGoroutines(t *testing.T) {
+	assert := assert.New(t)
+	lf, _ := getLoggerFactory()
+	before := runtime.NumGoroutine()
+
+	cancels := make([]context.CancelFunc, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancels = append(cancels, cancel)
+
+		lf.GetLogger(ctx)
+		lf.GetLogger(context.Background())
+	}
+
+	assert.LessOrEqual(runtime.NumGoroutine(), before, "goroutines should not be started")
+	assert.Equal(1001, lf.Stats().LiveLoggers, "Loggers should be counted")
+
+	for _, cancel := range cancels {
+		cancel()
+	}
+
+	assert.Eventually(func() bool { return lf.Stats().LiveLoggers == 1 }, time.Second, time.Millisecond,
+		"Loggers should be released")
+	// assert.Eventually runs condition on its own goroutine.
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	assert.LessOrEqual(runtime.NumGoroutine(), before, "goroutines should not be left behind")
+}
+
+func testExtractorLogsThroughFactory(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+
+	var lf LoggerFactory
+	var logged atomic.Bool
+	extractor := func(ctx context.Context) []Field {
+		// Logger of "extract" runs extractor too.
+		if logged.CompareAndSwap(false, true) {
+			lf.Named("extract").GetLogger(ctx).Println(Info, "extracting")
+		}
+
+		return []Field{String("extracted", "yes")}
+	}
+
+	lf = NewLoggerFactoryWithOptions(
+		FactoryOptions{ContextExtractors: []ContextExtractor{extractor}},
+		DestinationFunc(b, formatters.JSONFormatter, Info))
+
+	done := make(chan Logger)
+	go func() { done <- lf.GetLogger(context.TODO()) }()
+
+	select {
+	case l := <-done:
+		l.Println(Info, "done")
+	case <-time.After(time.Second):
+		assert.FailNow("GetLogger should not block")
+	}
+
+	dec := json.NewDecoder(b)
+	for _, expected := range []string{"extracting", "done"} {
+		m := decodeLog(assert, dec)
+
+		assert.Equal(expected, m.Message, "entries should be written")
+		assert.Equal("yes", m.Tags["extracted"], "extracted fields should be added")
+	}
+}
@@ -0,0 +1,122 @@
+package tinylog
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/andriiyaremenko/tinylog/formatters"
+)
+
+// Typed key-value pair attached to log entry.
+type Field = formatters.Field
+
+// Returns Field of formatters.StringKind.
+func String(key, value string) Field {
+	return formatters.String(key, value)
+}
+
+// Returns Field of formatters.StringsKind.
+func Strings(key string, value ...string) Field {
+	return formatters.Strings(key, value...)
+}
+
+// Returns Field of formatters.Int64Kind.
+func Int(key string, value int) Field {
+	return formatters.Int(key, value)
+}
+
+// Returns Field of formatters.Int64Kind.
+func Int64(key string, value int64) Field {
+	return formatters.Int64(key, value)
+}
+
+// Returns Field of formatters.Float64Kind.
+func Float64(key string, value float64) Field {
+	return formatters.Float64(key, value)
+}
+
+// Returns Field of formatters.BoolKind.
+func Bool(key string, value bool) Field {
+	return formatters.Bool(key, value)
+}
+
+// Returns Field of formatters.TimeKind.
+func Time(key string, value time.Time) Field {
+	return formatters.Time(key, value)
+}
+
+// Returns Field of formatters.DurationKind.
+func Duration(key string, value time.Duration) Field {
+	return formatters.Duration(key, value)
+}
+
+// Returns Field of formatters.ErrorKind with "error" as a key.
+func Err(err error) Field {
+	return formatters.Err(err)
+}
+
+// Returns Field of formatters.ErrorKind.
+func NamedErr(key string, err error) Field {
+	return formatters.NamedErr(key, err)
+}
+
+// Returns Field of formatters.ObjectKind.
+func Object(key string, value interface{}) Field {
+	return formatters.Object(key, value)
+}
+
+// Returns Field of the most suitable kind for value.
+func Any(key string, value interface{}) Field {
+	return formatters.Any(key, value)
+}
+
+// Returns fields built from alternating keys and values.
+// Field found in place of a key is taken as is.
+// Key without value is added as a value under "!BADKEY" key.
+func fieldsFromKeysAndValues(keysAndValues []interface{}) []Field {
+	fields := make([]Field, 0, len(keysAndValues)/2)
+
+	for i := 0; i < len(keysAndValues); i++ {
+		if field, ok := keysAndValues[i].(Field); ok {
+			fields = append(fields, field)
+			continue
+		}
+
+		if i == len(keysAndValues)-1 {
+			fields = append(fields, Any("!BADKEY", keysAndValues[i]))
+			break
+		}
+
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+
+		fields = append(fields, Any(key, keysAndValues[i+1]))
+		i++
+	}
+
+	return fields
+}
+
+// Returns copy of fields with extra fields set:
+// each of extra replaces Field with the same key or is appended if there is none.
+// fields are never modified, so they can be read without holding a lock.
+func withFields(fields []Field, extra ...Field) []Field {
+	result := make([]Field, len(fields), len(fields)+len(extra))
+	copy(result, fields)
+
+outer:
+	for _, field := range extra {
+		for i := range result {
+			if result[i].Key == field.Key {
+				result[i] = field
+				continue outer
+			}
+		}
+
+		result = append(result, field)
+	}
+
+	return result
+}
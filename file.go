@@ -0,0 +1,342 @@
+package tinylog
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andriiyaremenko/tinylog/formatters"
+)
+
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// Rotation configuration of FileWriter.
+type FileOptions struct {
+	// File is rotated before write that would make it bigger than MaxSize bytes.
+	// 0 = file is not rotated by size.
+	MaxSize int64
+	// File is rotated before first write after Interval passed since file was opened.
+	// 0 = file is not rotated by time.
+	Interval time.Duration
+	// Number of rotated files to keep.
+	// 0 = all rotated files are kept.
+	MaxBackups int
+	// Rotated files are compressed with gzip.
+	Compress bool
+}
+
+// Destination based on FileWriter as out.
+func FileDestination(path string, formatter formatters.LogFormatter, level int, opts FileOptions) Destination {
+	return DestinationFunc(NewFileWriter(path, opts), formatter, level)
+}
+
+// Returns new instance of FileWriter.
+// File is not opened until first write.
+func NewFileWriter(path string, opts FileOptions) *FileWriter {
+	return &FileWriter{path: path, opts: opts, now: time.Now}
+}
+
+// FileWriter is io.WriteCloser that writes to file and rotates it according to FileOptions.
+// Rotated files are renamed to path.<time of rotation> and put next to the file.
+type FileWriter struct {
+	mu sync.Mutex
+
+	path     string
+	opts     FileOptions
+	now      func() time.Time
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	// serializes compression and removal of rotated files.
+	millMu sync.Mutex
+	millWG sync.WaitGroup
+}
+
+// Writes p to file, opening or rotating it if needed.
+func (fw *FileWriter) Write(p []byte) (int, error) {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	if fw.file == nil {
+		if err := fw.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if fw.shouldRotate(int64(len(p))) {
+		if err := fw.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := fw.file.Write(p)
+	fw.size += int64(n)
+
+	return n, err
+}
+
+// Rotates file right away.
+func (fw *FileWriter) Rotate() error {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	if fw.file == nil {
+		if err := fw.open(); err != nil {
+			return err
+		}
+	}
+
+	return fw.rotate()
+}
+
+// Closes file, so it will be opened again on next write.
+// Useful if file was moved or removed by external tool.
+func (fw *FileWriter) Reopen() error {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	return fw.close()
+}
+
+// Closes file and waits for rotated files to be compressed and removed.
+func (fw *FileWriter) Close() error {
+	fw.mu.Lock()
+	err := fw.close()
+	fw.mu.Unlock()
+
+	fw.millWG.Wait()
+
+	return err
+}
+
+func (fw *FileWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(fw.path), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(fw.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	fw.file = file
+	fw.size = info.Size()
+	fw.openedAt = fw.now()
+
+	return nil
+}
+
+func (fw *FileWriter) close() error {
+	if fw.file == nil {
+		return nil
+	}
+
+	err := fw.file.Close()
+	fw.file = nil
+
+	return err
+}
+
+func (fw *FileWriter) shouldRotate(n int64) bool {
+	if fw.opts.MaxSize > 0 && fw.size > 0 && fw.size+n > fw.opts.MaxSize {
+		return true
+	}
+
+	return fw.opts.Interval > 0 && fw.now().Sub(fw.openedAt) >= fw.opts.Interval
+}
+
+func (fw *FileWriter) rotate() error {
+	if err := fw.close(); err != nil {
+		return err
+	}
+
+	backup := fw.path + "." + fw.now().Format(backupTimeFormat)
+	for i := 1; fileExists(backup) || fileExists(backup+".gz"); i++ {
+		backup = fmt.Sprintf("%s.%s-%d", fw.path, fw.now().Format(backupTimeFormat), i)
+	}
+
+	if err := os.Rename(fw.path, backup); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	fw.millWG.Add(1)
+	go fw.mill(backup)
+
+	return fw.open()
+}
+
+// Compresses backup if needed and removes rotated files that exceed MaxBackups.
+func (fw *FileWriter) mill(backup string) {
+	defer fw.millWG.Done()
+
+	fw.millMu.Lock()
+	defer fw.millMu.Unlock()
+
+	if fw.opts.Compress {
+		if err := compressFile(backup); err != nil {
+			printWriterError(fw.path, err)
+		}
+	}
+
+	if fw.opts.MaxBackups <= 0 {
+		return
+	}
+
+	backups, err := fw.backups()
+	if err != nil {
+		printWriterError(fw.path, err)
+		return
+	}
+
+	for len(backups) > fw.opts.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			printWriterError(fw.path, err)
+		}
+
+		backups = backups[1:]
+	}
+}
+
+// Returns rotated files from oldest to newest.
+// Only files named the way rotate names them are returned.
+func (fw *FileWriter) backups() ([]string, error) {
+	dir := filepath.Dir(fw.path)
+	prefix := filepath.Base(fw.path) + "."
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type backup struct {
+		path      string
+		rotatedAt time.Time
+		index     int
+	}
+
+	var found []backup
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+
+		rotatedAt, index, ok := parseBackupName(strings.TrimPrefix(entry.Name(), prefix))
+		if !ok {
+			continue
+		}
+
+		found = append(found, backup{filepath.Join(dir, entry.Name()), rotatedAt, index})
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].rotatedAt.Equal(found[j].rotatedAt) {
+			return found[i].index < found[j].index
+		}
+
+		return found[i].rotatedAt.Before(found[j].rotatedAt)
+	})
+
+	paths := make([]string, len(found))
+	for i, b := range found {
+		paths[i] = b.path
+	}
+
+	return paths, nil
+}
+
+// Parses suffix of rotated file name in form of "<time of rotation>[-N][.gz]".
+// Returns false if suffix was not produced by rotate.
+func parseBackupName(suffix string) (time.Time, int, bool) {
+	suffix = strings.TrimSuffix(suffix, ".gz")
+
+	if rotatedAt, err := time.Parse(backupTimeFormat, suffix); err == nil {
+		return rotatedAt, 0, true
+	}
+
+	dash := strings.LastIndex(suffix, "-")
+	if dash < 0 {
+		return time.Time{}, 0, false
+	}
+
+	index, err := strconv.Atoi(suffix[dash+1:])
+	if err != nil || index < 1 || strconv.Itoa(index) != suffix[dash+1:] {
+		return time.Time{}, 0, false
+	}
+
+	rotatedAt, err := time.Parse(backupTimeFormat, suffix[:dash])
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+
+	return rotatedAt, index, true
+}
+
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+
+	if _, err := io.Copy(gz, src); err != nil {
+		gz.Close()
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	// keep original modification time of rotated file.
+	if err := os.Chtimes(path+".gz", info.ModTime(), info.ModTime()); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func printWriterError(path string, err error) {
+	fmt.Println(
+		formatters.PaintText(
+			formatters.ANSIColorRed,
+			fmt.Sprintf("failed to manage log file %s: %s", path, err)))
+}
@@ -0,0 +1,206 @@
+package tinylog
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andriiyaremenko/tinylog/formatters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileWriter(t *testing.T) {
+	t.Run("FileWriter opens file on first write", testFileWriterOpensOnWrite)
+	t.Run("FileWriter rotates file by size and keeps MaxBackups", testFileWriterRotatesBySize)
+	t.Run("FileWriter removes only its own rotated files", testFileWriterKeepsUnrelatedFiles)
+	t.Run("FileWriter rotates file by interval", testFileWriterRotatesByInterval)
+	t.Run("FileWriter compresses rotated files", testFileWriterCompresses)
+	t.Run("FileWriter reopens file after Reopen", testFileWriterReopens)
+	t.Run("FileDestination can be used by LoggerFactory", testFileDestination)
+}
+
+func getBackups(assert *assert.Assertions, path string) []string {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	assert.NoError(err)
+
+	var backups []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), filepath.Base(path)+".") {
+			backups = append(backups, entry.Name())
+		}
+	}
+
+	return backups
+}
+
+func testFileWriterOpensOnWrite(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	fw := NewFileWriter(path, FileOptions{})
+
+	assert.NoFileExists(path, "file should not be opened before first write")
+
+	_, err := fw.Write([]byte("hello\n"))
+	assert.NoError(err)
+	assert.NoError(fw.Close())
+
+	b, err := os.ReadFile(path)
+	assert.NoError(err)
+	assert.Equal("hello\n", string(b), "file should contain written data")
+}
+
+func testFileWriterRotatesBySize(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "app.log")
+	fw := NewFileWriter(path, FileOptions{MaxSize: 10, MaxBackups: 2})
+	now := time.Now()
+	fw.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	for _, s := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := fw.Write([]byte(s))
+		assert.NoError(err)
+	}
+
+	assert.NoError(fw.Close())
+
+	b, err := os.ReadFile(path)
+	assert.NoError(err)
+	assert.Equal("fourth\n", string(b), "file should contain only data written after rotation")
+
+	backups := getBackups(assert, path)
+	assert.Len(backups, 2, "only MaxBackups rotated files should be kept")
+
+	b, err = os.ReadFile(filepath.Join(filepath.Dir(path), backups[len(backups)-1]))
+	assert.NoError(err)
+	assert.Equal("third\n", string(b), "newest rotated files should be kept")
+}
+
+func testFileWriterKeepsUnrelatedFiles(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app")
+	siblings := []string{"app.log", "app.config", "app.2006-01-02T15-04-05.000-x"}
+
+	for _, sibling := range siblings {
+		assert.NoError(os.WriteFile(filepath.Join(dir, sibling), []byte("keep me\n"), 0644))
+	}
+
+	fw := NewFileWriter(path, FileOptions{MaxSize: 10, MaxBackups: 1})
+	now := time.Now()
+	fw.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	for _, s := range []string{"first\n", "second\n", "third\n"} {
+		_, err := fw.Write([]byte(s))
+		assert.NoError(err)
+	}
+
+	assert.NoError(fw.Close())
+
+	for _, sibling := range siblings {
+		assert.FileExists(filepath.Join(dir, sibling), "files not rotated by FileWriter should be kept")
+	}
+
+	backups, err := fw.backups()
+	assert.NoError(err)
+	if !assert.Len(backups, 1, "only MaxBackups rotated files should be kept") {
+		return
+	}
+
+	b, err := os.ReadFile(backups[0])
+	assert.NoError(err)
+	assert.Equal("second\n", string(b), "newest rotated files should be kept")
+}
+
+func testFileWriterRotatesByInterval(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "app.log")
+	fw := NewFileWriter(path, FileOptions{Interval: time.Hour})
+	now := time.Now()
+	fw.now = func() time.Time { return now }
+
+	_, err := fw.Write([]byte("first\n"))
+	assert.NoError(err)
+
+	now = now.Add(30 * time.Minute)
+	_, err = fw.Write([]byte("second\n"))
+	assert.NoError(err)
+	assert.Empty(getBackups(assert, path), "file should not be rotated before interval passed")
+
+	now = now.Add(30 * time.Minute)
+	_, err = fw.Write([]byte("third\n"))
+	assert.NoError(err)
+	assert.NoError(fw.Close())
+	assert.Len(getBackups(assert, path), 1, "file should be rotated after interval passed")
+}
+
+func testFileWriterCompresses(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "app.log")
+	fw := NewFileWriter(path, FileOptions{Compress: true})
+
+	_, err := fw.Write([]byte("compress me\n"))
+	assert.NoError(err)
+	assert.NoError(fw.Rotate())
+	assert.NoError(fw.Close())
+
+	backups := getBackups(assert, path)
+	if !assert.Len(backups, 1, "rotated file should be kept") {
+		return
+	}
+
+	assert.True(strings.HasSuffix(backups[0], ".gz"), "rotated file should be compressed")
+
+	f, err := os.Open(filepath.Join(filepath.Dir(path), backups[0]))
+	assert.NoError(err)
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	assert.NoError(err)
+
+	b, err := io.ReadAll(gz)
+	assert.NoError(err)
+	assert.Equal("compress me\n", string(b), "compressed file should contain rotated data")
+}
+
+func testFileWriterReopens(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "app.log")
+	fw := NewFileWriter(path, FileOptions{})
+
+	_, err := fw.Write([]byte("first\n"))
+	assert.NoError(err)
+	assert.NoError(os.Rename(path, path+".moved"))
+	assert.NoError(fw.Reopen())
+
+	_, err = fw.Write([]byte("second\n"))
+	assert.NoError(err)
+	assert.NoError(fw.Close())
+
+	b, err := os.ReadFile(path)
+	assert.NoError(err)
+	assert.Equal("second\n", string(b), "file should be opened again after Reopen")
+}
+
+func testFileDestination(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "app.log")
+	lf := NewLoggerFactory(DefaultDestination, FileDestination(path, formatters.JSONFormatter, Trace, FileOptions{}))
+	l := lf.GetLogger(context.TODO())
+
+	l.Println(Debug, "to file")
+
+	b, err := os.ReadFile(path)
+	assert.NoError(err)
+	assert.Contains(string(b), "to file", "message should be written to file")
+}
@@ -0,0 +1,138 @@
+package formatters
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Defines how path of caller file is printed.
+type PathMode int
+
+const (
+	// File name only: "handler.go".
+	BasePath PathMode = iota
+	// Import path of package followed by file name: "github.com/org/app/internal/api/handler.go".
+	PackagePath
+	// Path relative to module root: "internal/api/handler.go".
+	// Falls back to PackagePath if module of caller is unknown.
+	ModulePath
+	// Absolute path as recorded by compiler: "/home/user/app/internal/api/handler.go".
+	AbsolutePath
+)
+
+// Place logging function was called from.
+type Caller struct {
+	// Full function name: "github.com/org/app/internal/api.(*Handler).ServeHTTP".
+	Function string
+	// File path according to PathMode.
+	File string
+	Line int
+}
+
+// Returns caller in form of "file:line".
+// Returns empty string if caller was not looked up.
+func (c Caller) Location() string {
+	if c.File == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%v:%d", c.File, c.Line)
+}
+
+var (
+	modulesOnce sync.Once
+	modules     []string
+)
+
+// Returns caller at calldepth relative to the caller of LogFormatter.GetOutput that called callerAt.
+// Returns zero Caller if calldepth is negative.
+func callerAt(calldepth int, mode PathMode) Caller {
+	if calldepth < 0 {
+		return Caller{}
+	}
+
+	// skip callerAt and GetOutput.
+	pc, file, line, ok := runtime.Caller(calldepth + 2)
+
+	if !ok {
+		return Caller{File: "???"}
+	}
+
+	function := ""
+	if f := runtime.FuncForPC(pc); f != nil {
+		function = f.Name()
+	}
+
+	return Caller{Function: function, File: callerPath(function, file, mode), Line: line}
+}
+
+func callerPath(function, file string, mode PathMode) string {
+	base := filepath.Base(file)
+
+	switch mode {
+	case AbsolutePath:
+		return file
+	case PackagePath, ModulePath:
+		pkg := packageOf(function)
+		if pkg == "" {
+			return base
+		}
+
+		if mode == ModulePath {
+			if module := moduleOf(pkg); module != "" {
+				pkg = strings.TrimPrefix(strings.TrimPrefix(pkg, module), "/")
+			}
+		}
+
+		if pkg == "" {
+			return base
+		}
+
+		return pkg + "/" + base
+	default:
+		return base
+	}
+}
+
+// Returns import path of package function belongs to.
+func packageOf(function string) string {
+	slash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[slash+1:], ".")
+
+	if dot < 0 {
+		return ""
+	}
+
+	return function[:slash+1+dot]
+}
+
+// Returns path of module package belongs to, the longest one if modules are nested.
+// Returns empty string if module is not listed in build info.
+func moduleOf(pkg string) string {
+	modulesOnce.Do(func() {
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+
+		modules = append(modules, info.Main.Path)
+		for _, dep := range info.Deps {
+			modules = append(modules, dep.Path)
+		}
+
+		sort.Slice(modules, func(i, j int) bool { return len(modules[i]) > len(modules[j]) })
+	})
+
+	for _, module := range modules {
+		if module != "" && (pkg == module || strings.HasPrefix(pkg, module+"/")) {
+			return module
+		}
+	}
+
+	return ""
+}
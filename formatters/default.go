@@ -3,6 +3,7 @@ package formatters
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"math"
 	"sort"
 	"strings"
@@ -15,6 +16,64 @@ const (
 	messageSpace int = 50
 )
 
+// Column of a row printed by default Formatter.
+type Column int
+
+const (
+	LevelColumn Column = iota
+	DateColumn
+	MessageColumn
+	FileColumn
+	TagsColumn
+)
+
+// Defines if default Formatter colorizes its output.
+type ColorMode int
+
+const (
+	// Output is colorized if Destination out is a terminal,
+	// FORCE_COLOR and NO_COLOR environment variables take precedence.
+	ColorAuto ColorMode = iota
+	// Output is always colorized.
+	ColorAlways
+	// Output is never colorized, colors are removed from message and tags.
+	ColorNever
+)
+
+var defaultColumns = []Column{LevelColumn, DateColumn, MessageColumn, FileColumn, TagsColumn}
+
+// Configuration of default Formatter.
+type Options struct {
+	// Format of date column.
+	// Empty = time.RFC822.
+	TimeFormat string
+	// Number of printable characters in a row.
+	// 0 = 175.
+	Width int
+	// Use width of terminal if Destination out is a terminal, Width otherwise.
+	AutoWidth bool
+	// Minimum space for message before file and tags columns are moved to separate rows.
+	// 0 = 50.
+	MinMessageWidth int
+	// Order of columns in a row.
+	// Columns before MessageColumn are printed in front of message,
+	// columns after MessageColumn are aligned to the right of a row.
+	// Columns that are not listed are not printed.
+	// nil = LevelColumn, DateColumn, MessageColumn, FileColumn, TagsColumn.
+	Columns []Column
+	// Defines if output is colorized.
+	// ColorAuto is used by default.
+	Color ColorMode
+	// Colors of levels and columns.
+	// Zero value = DarkTheme().
+	Theme Theme
+	// Defines how path of caller file is printed in file column.
+	// BasePath is used by default.
+	PathMode PathMode
+	// Print full function name of caller in file column.
+	CallerFunction bool
+}
+
 // Returns default instance of Formatter with time.RFC822 as timeFormat.
 // Formatter that returns log message in form of colorized plain text rows of fixed length.
 func Default() LogFormatter {
@@ -24,18 +83,62 @@ func Default() LogFormatter {
 // Returns default instance of Formatter with timeFormat.
 // Formatter that returns log message in form of colorized plain text rows of fixed length.
 func New(timeFormat string) LogFormatter {
-	return &defaultFormatter{timeFormat: timeFormat}
+	return NewWithOptions(Options{TimeFormat: timeFormat})
+}
+
+// Returns instance of default Formatter configured with opts.
+// Formatter that returns log message in form of colorized plain text rows of fixed length.
+func NewWithOptions(opts Options) LogFormatter {
+	if opts.TimeFormat == "" {
+		opts.TimeFormat = time.RFC822
+	}
+
+	if opts.Width <= 0 {
+		opts.Width = totalSpace
+	}
+
+	if opts.MinMessageWidth <= 0 {
+		opts.MinMessageWidth = messageSpace
+	}
+
+	if opts.Columns == nil {
+		opts.Columns = defaultColumns
+	}
+
+	if opts.Theme == (Theme{}) {
+		opts.Theme = DarkTheme()
+	}
+
+	return &defaultFormatter{opts: opts, colorize: shouldColorize(opts.Color, nil)}
 }
 
 type defaultFormatter struct {
-	timeFormat string
+	opts     Options
+	out      io.Writer
+	colorize bool
+}
+
+func (df *defaultFormatter) ForWriter(out io.Writer) LogFormatter {
+	return &defaultFormatter{opts: df.opts, out: out, colorize: shouldColorize(df.opts.Color, out)}
+}
+
+func (df *defaultFormatter) width() int {
+	if !df.opts.AutoWidth || df.out == nil {
+		return df.opts.Width
+	}
+
+	if width, ok := terminalWidth(df.out); ok {
+		return width
+	}
+
+	return df.opts.Width
 }
 
-func (df *defaultFormatter) GetOutput(level int, message string, tags map[string][]string, calldepth int) []byte {
-	now := time.Now() // get this early.
-	printFile := level <= 1 || level == 5
+func (df *defaultFormatter) GetOutput(level int, message string, fields []Field, calldepth int) []byte {
+	now := entryTime(fields) // get this early.
+	printFile := (level <= 1 || level == 5) && calldepth >= 0
 
-	if level == 0 || level == 5 {
+	if level == 0 || level == 5 || !df.colorize {
 		message = DecolorizeString(message)
 	}
 
@@ -47,212 +150,372 @@ func (df *defaultFormatter) GetOutput(level int, message string, tags map[string
 
 	message = buf.String()
 
-	levelS, color := getLevelTextAndColor(level)
-	file, line := getFileAndLine(calldepth + 1)
-	dateString := now.Format(df.timeFormat)
-	foundAtString := fmt.Sprintf("at %v:%d", file, line)
-
-	levelSection := PaintText(color, levelS) + " "
-	dateSection := PaintText(ANSIColorGray, dateString) + " "
-	fileSection := " " + PaintText(ANSIColorGray, foundAtString)
-
-	spaceForLevel := len(levelS) + 1
-	spaceForDate := len(dateString) + 1
-	spaceForFile := LenPrintableText(foundAtString) + 1
-	spaceForTags := 0
-	messageLength := LenPrintableText(message)
-
-	if !printFile {
-		fileSection = ""
-		spaceForFile = 0
+	for strings.HasSuffix(message, "\n") {
+		message = strings.TrimSuffix(message, "\n")
 	}
 
-	var keys []string
+	levelS, _ := getLevelTextAndColor(level)
+	caller := callerAt(calldepth, df.opts.PathMode)
+	theme := df.opts.Theme
 
-	for k := range tags {
-		keys = append(keys, k)
+	// sections without color are printed as is.
+	if !df.colorize {
+		theme = Theme{}
 	}
 
-	sort.Strings(keys)
+	var stack []Frame
+	tags := make([]Field, 0, len(fields))
 
-	var tagsSectionBuilder strings.Builder
-	for i, k := range keys {
-		if i > 0 {
-			tagsSectionBuilder.WriteByte(';')
-			tagsSectionBuilder.WriteByte(' ')
+	for _, field := range fields {
+		if field.Key == StackKey && field.Kind == StackKind {
+			stack = field.Value.([]Frame)
+			continue
+		}
 
-			spaceForTags += 2
+		if field.isEntryTime() {
+			continue
 		}
 
-		tagsSectionBuilder.WriteString(PaintText(color, k))
-		tagsSectionBuilder.WriteByte('=')
+		tags = append(tags, field)
+	}
+
+	var prefix, suffix []column
+	beforeMessage := true
+
+	for _, c := range df.opts.Columns {
+		var col column
+
+		switch c {
+		case MessageColumn:
+			beforeMessage = false
+			continue
+		case LevelColumn:
+			col = column{items: []section{{text: levelS, color: theme.levelColor(level)}}}
+		case DateColumn:
+			if now.IsZero() {
+				continue
+			}
 
-		spaceForTags += LenPrintableText(k) + 1
+			col = column{items: []section{{text: now.Format(df.opts.TimeFormat), color: theme.Date}}}
+		case FileColumn:
+			if !printFile {
+				continue
+			}
 
-		for j, s := range tags[k] {
-			if j > 0 {
-				tagsSectionBuilder.WriteByte(',')
+			text := "at " + caller.Location()
+			if df.opts.CallerFunction && caller.Function != "" {
+				text = "at " + caller.Function + " " + caller.Location()
+			}
 
-				spaceForTags++
+			col = column{items: []section{{text: text, color: theme.File}}}
+		case TagsColumn:
+			if len(tags) == 0 {
+				continue
 			}
 
-			tagsSectionBuilder.WriteString(PaintText(color, s))
+			col = tagsColumn(tags, theme.tagsColor(level), !df.colorize)
+		default:
+			continue
+		}
+
+		if beforeMessage {
+			prefix = append(prefix, col)
+			continue
+		}
 
-			spaceForTags += LenPrintableText(s)
+		suffix = append(suffix, col)
+	}
+
+	paint := func(messagePart string) string {
+		if (level != 0 && level != 5) || theme.levelColor(level) == "" {
+			return messagePart
 		}
+
+		return PaintText(theme.levelColor(level), messagePart)
 	}
 
-	tagsSection := " " + tagsSectionBuilder.String()
+	b := layoutRows(prefix, message, paint, suffix, df.width(), df.opts.MinMessageWidth)
 
-	if len(tagsSection) > messageSpace {
-		panic(
-			fmt.Sprintf(
-				"tag %q is too long - consider moving part of into message or using other formatter",
-				strings.Trim(DecolorizeString(tagsSection), " "),
-			),
-		)
+	return append(b, stackRows(stack, columnsWidth(prefix)+2, df.width(), theme.File)...)
+}
+
+// Returns row for every frame indented by indent spaces and padded to width.
+// Rows of frames that do not fit in width are not padded.
+func stackRows(stack []Frame, indent, width int, color Color) []byte {
+	var b []byte
+	for _, frame := range stack {
+		row := section{text: frame.String(), color: color}
+
+		b = append(b, padSpaces(indent)...)
+		b = append(b, row.render()...)
+		b = append(b, padSpaces(width-indent-row.width())...)
+		b = append(b, '\n')
 	}
 
-	spaceForTags++
+	return b
+}
 
-	spaceForEverythingElse := spaceForLevel + spaceForDate + spaceForFile + spaceForTags
-	spaceForMessage := totalSpace - spaceForEverythingElse
-	messageSpace := messageSpace
+// Part of a row printed in one color.
+type section struct {
+	text  string
+	color Color
+}
+
+func (s section) width() int {
+	return LenPrintableText(s.text)
+}
 
-	if messageLength < messageSpace {
-		messageSpace = messageLength
+// Section without color is rendered as is.
+func (s section) render() string {
+	if s.color == "" {
+		return s.text
 	}
 
-	fileOnSeparateLine := false
-	tagsAndFileOnSeparateLine := false
+	return PaintText(s.color, s.text)
+}
 
-	for {
-		if !fileOnSeparateLine && spaceForMessage < messageSpace {
-			fileOnSeparateLine = true
-			spaceForMessage += spaceForFile
-			continue
+// Prefix column is followed by space, suffix column is preceded by space.
+// Items of a column are separated by "; ".
+type column struct {
+	items []section
+}
+
+func (c column) width() int {
+	width := 1
+
+	for i, item := range c.items {
+		if i > 0 {
+			width += 2
 		}
 
-		if spaceForMessage < messageSpace {
-			tagsAndFileOnSeparateLine = true
-			spaceForMessage += spaceForTags
+		width += item.width()
+	}
+
+	return width
+}
+
+func (c column) render(asPrefix bool) string {
+	var b strings.Builder
+
+	if !asPrefix {
+		b.WriteByte(' ')
+	}
+
+	for i, item := range c.items {
+		if i > 0 {
+			b.WriteString("; ")
 		}
 
-		break
+		b.WriteString(item.render())
 	}
 
-	for strings.HasSuffix(message, "\n") {
-		message = strings.TrimSuffix(message, "\n")
+	if asPrefix {
+		b.WriteByte(' ')
 	}
 
-	var b []byte
-	if messageLength > spaceForMessage || strings.Contains(message, "\n") {
-		for _, messagePart := range splitMessageIntoRows(message, spaceForMessage) {
-			spaceForMessagePart := LenPrintableText(messagePart)
+	return b.String()
+}
 
-			if level == 0 {
-				messagePart = PaintText(ColorTrace, messagePart)
-			}
+// Colors are removed from tag values if decolorize is true.
+func tagsColumn(fields []Field, color Color, decolorize bool) column {
+	sorted := make([]Field, len(fields))
+	copy(sorted, fields)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Key < sorted[j].Key })
 
-			if level == 5 {
-				messagePart = PaintText(ColorFatal, messagePart)
-			}
+	items := make([]section, 0, len(sorted))
+	for _, field := range sorted {
+		value := field.Text()
 
-			b = append(b, getFinalOutput(levelSection, spaceForLevel, dateSection, spaceForDate,
-				tagsSection, spaceForTags, tagsAndFileOnSeparateLine,
-				fileSection, spaceForFile, fileOnSeparateLine,
-				messagePart, spaceForMessagePart, totalSpace)...)
-			b = append(b, '\n')
+		if field.Kind == ErrorKind && field.Value != nil {
+			value += " (" + NewErrorInfo(field.Value.(error)).TypeChain() + ")"
+		}
+
+		// new lines would break rows alignment.
+		value = strings.ReplaceAll(value, "\n", `\n`)
+
+		if decolorize {
+			value = DecolorizeString(value)
 		}
 
-		return b
+		items = append(items, section{text: field.Key + "=" + value, color: color})
+	}
+
+	return column{items: items}
+}
+
+// Returns rows of exactly width printable characters:
+// prefix columns, message part padded with spaces and suffix columns aligned to the right.
+// Suffix columns that leave less than minMessage space for message (or less than message length if it is shorter)
+// are moved to separate rows in order they are listed, columns wider than a row are wrapped.
+// If there is no space for message at all, rows are not aligned.
+func layoutRows(prefix []column, message string, paint func(string) string, suffix []column,
+	width, minMessage int) []byte {
+	prefixWidth := 0
+	for _, col := range prefix {
+		prefixWidth += col.width()
+	}
+
+	available := width - prefixWidth
+	messageLength := LenPrintableText(message)
+
+	if messageLength < minMessage {
+		minMessage = messageLength
+	}
+
+	if minMessage < 1 {
+		minMessage = 1
 	}
 
-	if level == 0 {
-		message = PaintText(ColorTrace, message)
+	if available < minMessage || available < 2 {
+		return plainRows(prefix, message, paint, suffix)
 	}
 
-	if level == 5 {
-		message = PaintText(ColorFatal, message)
+	inline := suffix
+	spaceForMessage := available - columnsWidth(inline)
+
+	for spaceForMessage < minMessage {
+		inline = inline[1:]
+		spaceForMessage = available - columnsWidth(inline)
+	}
+
+	spilled := suffix[:len(suffix)-len(inline)]
+	renderedPrefix := renderColumns(prefix, true)
+	renderedInline := renderColumns(inline, false)
+	spilledRows := wrapColumns(spilled, available)
+
+	messageParts := []string{message}
+	if messageLength > spaceForMessage || strings.Contains(message, "\n") {
+		messageParts = splitMessageIntoRows(message, spaceForMessage)
 	}
 
-	b = append(b, getFinalOutput(levelSection, spaceForLevel, dateSection, spaceForDate,
-		tagsSection, spaceForTags, tagsAndFileOnSeparateLine,
-		fileSection, spaceForFile, fileOnSeparateLine,
-		message, messageLength, totalSpace)...)
-	b = append(b, '\n')
+	var b []byte
+	for _, messagePart := range messageParts {
+		b = append(b, renderedPrefix...)
+		b = append(b, paint(messagePart)...)
+		b = append(b, padSpaces(spaceForMessage-LenPrintableText(messagePart))...)
+		b = append(b, renderedInline...)
+		b = append(b, '\n')
+
+		for _, row := range spilledRows {
+			b = append(b, renderedPrefix...)
+			b = append(b, padSpaces(available-columnsWidth(row))...)
+			b = append(b, renderColumns(row, false)...)
+			b = append(b, '\n')
+		}
+	}
 
 	return b
 }
 
-func getFinalOutput(levelSection string, levelLength int, dateSection string, dateLength int,
-	tagsSection string, tagsLength int, tagsAndFileAreOnSeparateLine bool,
-	fileSection string, fileLength int, fileIsOnSeparateLine bool,
-	message string, messageLength int, totalLength int) []byte {
+// Returns rows of prefix columns, message and suffix columns separated by single space.
+func plainRows(prefix []column, message string, paint func(string) string, suffix []column) []byte {
+	renderedPrefix := renderColumns(prefix, true)
+
 	var b []byte
-	b = append(b, []byte(levelSection)...)
-	b = append(b, []byte(dateSection)...)
-	b = append(b, []byte(message)...)
-	padSpaces := func(count int) []byte {
-		if count < 0 {
-			switch {
-			case tagsLength > 0 && fileLength > 0:
-				panic(
-					fmt.Sprintf("message %q\n\twith tags %q\n\tand file %q\n\tcaused negative (%d) pad spaces count",
-						message,
-						strings.Trim(DecolorizeString(tagsSection), " "),
-						strings.Trim(DecolorizeString(fileSection), " "),
-						count,
-					),
-				)
-			case tagsLength > 0:
-				panic(
-					fmt.Sprintf("message %q\n\twith tags %q\n\tcaused negative (%d) pad spaces count",
-						message,
-						strings.Trim(DecolorizeString(tagsSection), " "),
-						count,
-					),
-				)
-			case fileLength > 0:
-				panic(
-					fmt.Sprintf("message %q with file %q\n\tcaused negative (%d) pad spaces count",
-						message,
-						strings.Trim(DecolorizeString(fileSection), " "),
-						count,
-					),
-				)
-			default:
-				panic(fmt.Sprintf("message %q caused negative (%d) pad spaces count", message, count))
+	for i, messageRow := range strings.Split(message, "\n") {
+		b = append(b, renderedPrefix...)
+		b = append(b, paint(messageRow)...)
+
+		if i == 0 {
+			b = append(b, renderColumns(suffix, false)...)
+		}
+
+		b = append(b, '\n')
+	}
+
+	return b
+}
+
+// Packs columns into rows no wider than width.
+// Column wider than width is split into several columns by its items, items wider than width are broken.
+func wrapColumns(columns []column, width int) [][]column {
+	var rows [][]column
+	var row []column
+
+	flush := func() {
+		if len(row) > 0 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+
+	for _, col := range columns {
+		if col.width() <= width {
+			if columnsWidth(row)+col.width() > width {
+				flush()
 			}
+
+			row = append(row, col)
+			continue
 		}
 
-		return []byte(strings.Repeat(" ", count))
+		flush()
+
+		first := len(rows)
+		for _, item := range col.items {
+			for _, piece := range breakSection(item, width-1) {
+				last := len(rows) - 1
+				if last >= first && rows[last][0].width()+2+piece.width() <= width {
+					rows[last][0].items = append(rows[last][0].items, piece)
+					continue
+				}
+
+				rows = append(rows, []column{{items: []section{piece}}})
+			}
+		}
 	}
 
-	switch {
-	case tagsAndFileAreOnSeparateLine:
-		b = append(b, padSpaces(totalLength-levelLength-dateLength-messageLength)...)
-		b = append(b, '\n')
-		b = append(b, []byte(levelSection)...)
-		b = append(b, []byte(dateSection)...)
-		b = append(b, padSpaces(totalLength-levelLength-dateLength-tagsLength-fileLength)...)
-		b = append(b, []byte(fileSection)...)
-		b = append(b, []byte(tagsSection)...)
-	case fileIsOnSeparateLine:
-		b = append(b, padSpaces(totalLength-levelLength-dateLength-messageLength-tagsLength)...)
-		b = append(b, []byte(tagsSection)...)
-		b = append(b, '\n')
-		b = append(b, []byte(levelSection)...)
-		b = append(b, []byte(dateSection)...)
-		b = append(b, padSpaces(totalLength-levelLength-dateLength-fileLength)...)
-		b = append(b, []byte(fileSection)...)
-	default:
-		b = append(b, padSpaces(totalLength-levelLength-dateLength-messageLength-tagsLength-fileLength)...)
-		b = append(b, []byte(fileSection)...)
-		b = append(b, []byte(tagsSection)...)
+	flush()
+
+	return rows
+}
+
+// Breaks section into sections no wider than width.
+func breakSection(s section, width int) []section {
+	if s.width() <= width {
+		return []section{s}
 	}
 
-	return b
+	runes := []rune(DecolorizeString(s.text))
+
+	var pieces []section
+	for len(runes) > 0 {
+		n := width
+		if n > len(runes) {
+			n = len(runes)
+		}
+
+		pieces = append(pieces, section{text: string(runes[:n]), color: s.color})
+		runes = runes[n:]
+	}
+
+	return pieces
+}
+
+func columnsWidth(columns []column) int {
+	width := 0
+	for _, col := range columns {
+		width += col.width()
+	}
+
+	return width
+}
+
+func renderColumns(columns []column, asPrefix bool) string {
+	var b strings.Builder
+	for _, col := range columns {
+		b.WriteString(col.render(asPrefix))
+	}
+
+	return b.String()
+}
+
+func padSpaces(count int) []byte {
+	if count < 0 {
+		count = 0
+	}
+
+	return []byte(strings.Repeat(" ", count))
 }
 
 func splitMessageIntoRows(message string, spaceForMessage int) []string {
@@ -1,8 +1,11 @@
 package formatters
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -32,12 +35,26 @@ func TestDefaultLoggerFormatter(t *testing.T) {
 	t.Run("GetOutput would show file location for TRACE, DEBUG and FATAL",
 		testGetOutputShowsFileForTraceDebugFatalOnly)
 	t.Run("GetOutput would handle more than one row long tags", testLongTags)
+	t.Run("GetOutput prints typed fields as tags", testGetOutputPrintsTypedFields)
+	t.Run("GetOutput would wrap tags longer than a row", testWrapsTagsLongerThanRow)
+	t.Run("GetOutput would not align rows if there is no space for message", testFallsBackToPlainRows)
+	t.Run("NewWithOptions configures width of rows", testOptionsWidth)
+	t.Run("NewWithOptions configures order of columns", testOptionsColumns)
+	t.Run("NewWithOptions with AutoWidth uses Width if out is not a terminal", testOptionsAutoWidth)
+	t.Run("NewWithOptions configures if output is colorized", testOptionsColor)
+	t.Run("GetOutput is not colorized if NO_COLOR is set", testNoColorEnv)
+	t.Run("GetOutput is colorized if FORCE_COLOR is set", testForceColorEnv)
+	t.Run("GetOutput is not colorized if out is not a terminal", testNoColorForNonTerminal)
+	t.Run("NewWithOptions configures colors with Theme", testOptionsTheme)
+	t.Run("GetOutput prints stack trace as indented rows", testStackRows)
+	t.Run("GetOutput prints error with types of wrapped errors", testErrorTag)
+	t.Run("NewWithOptions configures caller in file column", testOptionsCaller)
 }
 
 func testGetOutputReturnsRows(t *testing.T) {
 	assert := assert.New(t)
 	f := Default()
-	b := f.GetOutput(0, short, make(map[string][]string), 0)
+	b := f.GetOutput(0, short, nil, 0)
 	s := string(b)
 	r := []rune(s)
 
@@ -47,19 +64,19 @@ func testGetOutputReturnsRows(t *testing.T) {
 func testGetOutputReturnsRowsOfExactLengthForWithFileWithoutTags(t *testing.T) {
 	assert := assert.New(t)
 	f := Default()
-	b := f.GetOutput(0, PaintText(ANSIColorBlue, short), make(map[string][]string), 0)
+	b := f.GetOutput(0, PaintText(ANSIColorBlue, short), nil, 0)
 	s := string(b)
 	length := LenPrintableText(s[:len(s)-1])
 
 	assert.Equal(lenWithFileWithoutTags, length, "should be of exact length")
 
-	b = f.GetOutput(0, medium, make(map[string][]string), 0)
+	b = f.GetOutput(0, medium, nil, 0)
 	s = string(b)
 	length = LenPrintableText(s[:len(s)-1])
 
 	assert.Equal(lenWithFileWithoutTags, length, "should be of exact length")
 
-	b = f.GetOutput(0, long, make(map[string][]string), 0)
+	b = f.GetOutput(0, long, nil, 0)
 	s = string(b)
 
 	for _, s := range strings.Split(s[:len(s)-1], "\n") {
@@ -72,8 +89,7 @@ func testGetOutputReturnsRowsOfExactLengthForWithFileWithoutTags(t *testing.T) {
 func testGetOutputReturnsRowsOfExactLength(t *testing.T) {
 	assert := assert.New(t)
 	f := Default()
-	tags := make(map[string][]string)
-	tags["tag"] = []string{"cool tag"}
+	tags := []Field{Strings("tag", "cool tag")}
 	b := f.GetOutput(0, PaintText(ColorFatal, short), tags, 0)
 	s := string(b)
 	length := LenPrintableText(s[:len(s)-1])
@@ -99,7 +115,7 @@ func testGetOutputReturnsRowsOfExactLength(t *testing.T) {
 func testGetOutputReturnsSeveralRowsForLongMessages(t *testing.T) {
 	assert := assert.New(t)
 	f := Default()
-	b := f.GetOutput(0, long, make(map[string][]string), 0)
+	b := f.GetOutput(0, long, nil, 0)
 	s := string(b)
 	// remove last new line
 	rows := strings.Split(s[:len(s)-1], "\n")
@@ -110,7 +126,7 @@ func testGetOutputReturnsSeveralRowsForLongMessages(t *testing.T) {
 func testGetOutputReturnsSeveralRowsMessagesWithNewLines(t *testing.T) {
 	assert := assert.New(t)
 	f := Default()
-	b := f.GetOutput(0, withNewLine, make(map[string][]string), 0)
+	b := f.GetOutput(0, withNewLine, nil, 0)
 	s := string(b)
 	// remove last new line
 	rows := strings.Split(s[:len(s)-1], "\n")
@@ -122,7 +138,7 @@ func testGetOutputShowsFileForTraceDebugFatalOnly(t *testing.T) {
 	assert := assert.New(t)
 	f := Default()
 	for i := 0; i <= 5; i++ {
-		b := f.GetOutput(i, short, make(map[string][]string), 0)
+		b := f.GetOutput(i, short, nil, 0)
 		if i <= 1 || i == 5 {
 			// default_test.go - name of this file, where it was called
 			assert.Containsf(string(b), "default_test.go", "should print file location for %d", i)
@@ -135,14 +151,13 @@ func testGetOutputShowsFileForTraceDebugFatalOnly(t *testing.T) {
 }
 
 func testLongTags(t *testing.T) {
-	t.Skip("More than one row tags are panicking for now... Skipping...")
 	message := "http://www.thessaliaradio.online/ - \x1b[32mattempt #1\x1b[0m \x1b[34m600ms\x1b[0m"
 	assert := assert.New(t)
 	f := Default()
-	b := f.GetOutput(2, message, map[string][]string{
-		"link": []string{
+	b := f.GetOutput(2, message, []Field{
+		Strings("link",
 			`{"login":"test","password":"test123","cms":"","method":"","address":"http://perdetata.bg/","dateAdded":"2021-06-27T17:19:19.806708+03:00"}`,
-		},
+		),
 	}, 0)
 	s := string(b)
 
@@ -152,3 +167,196 @@ func testLongTags(t *testing.T) {
 		assert.Equal(lenDefault, length, "should be of exact length")
 	}
 }
+
+func testGetOutputPrintsTypedFields(t *testing.T) {
+	assert := assert.New(t)
+	f := Default()
+
+	for _, tc := range []struct {
+		field    Field
+		expected string
+	}{
+		{Int("id", 42), "id=42"},
+		{Bool("admin", true), "admin=true"},
+		{Duration("took", 1500*time.Millisecond), "took=1.5s"},
+		{Strings("user", "me", "cat"), "user=me,cat"},
+	} {
+		b := f.GetOutput(2, short, []Field{tc.field}, 0)
+
+		assert.Containsf(DecolorizeString(string(b)), tc.expected, "%s field should be printed", tc.field.Key)
+	}
+}
+
+func testWrapsTagsLongerThanRow(t *testing.T) {
+	assert := assert.New(t)
+	f := Default()
+	long := strings.Repeat("x", 2*lenDefault)
+	b := f.GetOutput(1, short, []Field{String("long", long), String("multi", "line\nvalue")}, 0)
+	s := string(b)
+	rows := strings.Split(s[:len(s)-1], "\n")
+
+	for _, s := range rows {
+		assert.Equal(lenDefault, LenPrintableText(s), "should be of exact length")
+	}
+
+	assert.Contains(s, "multi=line\\nvalue", "new lines in tags should be escaped")
+	assert.Equal(2*lenDefault, strings.Count(s, "x"), "long tag should not be truncated")
+}
+
+func testFallsBackToPlainRows(t *testing.T) {
+	assert := assert.New(t)
+	f := New(strings.Repeat("2006-01-02 ", 20))
+	b := f.GetOutput(1, withNewLine, []Field{String("tag", "cool tag")}, 0)
+	s := DecolorizeString(string(b))
+	rows := strings.Split(s[:len(s)-1], "\n")
+
+	assert.Len(rows, 2, "should print every message row")
+	assert.Contains(rows[0], " hello logger at default_test.go:", "should print message followed by file")
+	assert.Contains(rows[0], "tag=cool tag", "should print tags")
+	assert.Contains(rows[1], " my old friend", "should print every message row")
+}
+
+func testOptionsWidth(t *testing.T) {
+	assert := assert.New(t)
+	f := NewWithOptions(Options{Width: 80, MinMessageWidth: 20})
+	b := f.GetOutput(1, long, []Field{String("tag", "cool tag")}, 0)
+	s := string(b)
+
+	for _, s := range strings.Split(s[:len(s)-1], "\n") {
+		assert.Equal(80, LenPrintableText(s), "should be of configured length")
+	}
+}
+
+func testOptionsColumns(t *testing.T) {
+	assert := assert.New(t)
+	f := NewWithOptions(Options{
+		TimeFormat: "2006",
+		Width:      80,
+		Columns:    []Column{DateColumn, LevelColumn, MessageColumn, TagsColumn},
+	})
+	b := f.GetOutput(1, short, []Field{String("tag", "cool")}, 0)
+	s := DecolorizeString(string(b))
+
+	assert.Regexp(`^\d{4} DEBUG hello logger +tag=cool\n$`, s, "columns should be printed in configured order")
+	assert.NotContains(s, "default_test.go", "columns that are not listed should not be printed")
+}
+
+func testOptionsAutoWidth(t *testing.T) {
+	assert := assert.New(t)
+	f := NewWithOptions(Options{Width: 80, AutoWidth: true}).(WriterFormatter).ForWriter(new(strings.Builder))
+	b := f.GetOutput(2, short, nil, 0)
+	s := string(b)
+
+	assert.Equal(80, LenPrintableText(s[:len(s)-1]), "should be of configured length")
+}
+
+func testOptionsColor(t *testing.T) {
+	assert := assert.New(t)
+	message := PaintText(ANSIColorBlue, short)
+	fields := []Field{String("tag", PaintText(ANSIColorBlue, "cool"))}
+
+	b := NewWithOptions(Options{Color: ColorNever}).GetOutput(0, message, fields, 0)
+	assert.Equal(DecolorizeString(string(b)), string(b), "should not contain colors")
+	assert.Equal(lenDefault, len(b)-1, "should be of exact length")
+
+	b = NewWithOptions(Options{Color: ColorAlways}).(WriterFormatter).
+		ForWriter(new(strings.Builder)).
+		GetOutput(2, message, fields, 0)
+	assert.Contains(string(b), ANSIColorBlue, "should contain colors")
+}
+
+func testNoColorEnv(t *testing.T) {
+	assert := assert.New(t)
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("NO_COLOR", "1")
+
+	b := Default().GetOutput(2, PaintText(ANSIColorBlue, short), []Field{String("tag", "cool")}, 0)
+	assert.Equal(DecolorizeString(string(b)), string(b), "should not contain colors")
+}
+
+func testForceColorEnv(t *testing.T) {
+	assert := assert.New(t)
+	t.Setenv("FORCE_COLOR", "1")
+	t.Setenv("NO_COLOR", "1")
+
+	b := Default().(WriterFormatter).ForWriter(new(strings.Builder)).GetOutput(2, short, nil, 0)
+	assert.NotEqual(DecolorizeString(string(b)), string(b), "should contain colors")
+}
+
+func testNoColorForNonTerminal(t *testing.T) {
+	assert := assert.New(t)
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("NO_COLOR", "")
+
+	b := Default().(WriterFormatter).ForWriter(new(strings.Builder)).GetOutput(5, short, nil, 0)
+	assert.Equal(DecolorizeString(string(b)), string(b), "should not contain colors")
+}
+
+func testOptionsTheme(t *testing.T) {
+	assert := assert.New(t)
+	theme := Theme{
+		Info:  Color256(28),
+		Fatal: TrueColor(255, 0, 0),
+		Date:  Color256(240),
+		File:  Color256(241),
+		Tags:  TrueColor(0, 0, 255),
+	}
+	f := NewWithOptions(Options{Color: ColorAlways, Theme: theme})
+
+	b := f.GetOutput(2, short, []Field{String("tag", "cool")}, 0)
+	s := string(b)
+	assert.Contains(s, PaintText(Color256(28), " INFO"), "level should be painted in theme color")
+	assert.Contains(s, "\033[38;5;240m", "date should be painted in theme color")
+	assert.Contains(s, PaintText(TrueColor(0, 0, 255), "tag=cool"), "tags should be painted in theme color")
+	assert.Equal(lenDefault, LenPrintableText(s[:len(s)-1]), "should be of exact length")
+
+	b = f.GetOutput(5, short, nil, 0)
+	s = string(b)
+	assert.Contains(s, PaintText(TrueColor(255, 0, 0), short), "FATAL message should be painted in theme color")
+	assert.Contains(s, "\033[38;5;241mat default_test.go:", "file should be painted in theme color")
+
+	b = NewWithOptions(Options{Color: ColorAlways, Theme: MonochromeTheme()}).GetOutput(2, short, nil, 0)
+	s = string(b)
+	assert.Contains(s, " INFO "+string(ANSIFontFaint), "level without color should be printed as is")
+	assert.NotContains(s, string(ANSIColorGreen), "should not contain colors of default theme")
+}
+
+func testStackRows(t *testing.T) {
+	assert := assert.New(t)
+	f := NewWithOptions(Options{Width: 80, Columns: []Column{LevelColumn, MessageColumn, TagsColumn}})
+	stack := Stack([]Frame{
+		{Function: "main.run", File: "/app/main.go", Line: 12},
+		{Function: "main.main", File: "/app/main.go", Line: 4},
+	})
+	b := f.GetOutput(4, short, []Field{String("tag", "cool"), stack}, 0)
+	s := DecolorizeString(string(b))
+	rows := strings.Split(s[:len(s)-1], "\n")
+
+	assert.Len(rows, 3, "should print row for every frame")
+	assert.NotContains(rows[0], "main.run", "stack should not be printed as a tag")
+	assert.Regexp(`^ {8}main\.run \(/app/main\.go:12\) +$`, rows[1], "frame should be indented")
+	assert.Regexp(`^ {8}main\.main \(/app/main\.go:4\) +$`, rows[2], "frame should be indented")
+
+	for _, row := range rows {
+		assert.Equal(80, LenPrintableText(row), "should be of exact length")
+	}
+}
+
+func testErrorTag(t *testing.T) {
+	assert := assert.New(t)
+	f := NewWithOptions(Options{Color: ColorNever})
+	err := fmt.Errorf("save user: %w", errors.New("oops"))
+	b := f.GetOutput(4, short, []Field{Err(err)}, 0)
+
+	assert.Contains(string(b), "error=save user: oops (*fmt.wrapError > *errors.errorString)",
+		"error should be printed with its type")
+}
+
+func testOptionsCaller(t *testing.T) {
+	assert := assert.New(t)
+	f := NewWithOptions(Options{PathMode: ModulePath, CallerFunction: true, Color: ColorNever})
+	b := f.GetOutput(1, short, nil, 0)
+
+	assert.Regexp(`at github\.com/andriiyaremenko/tinylog/formatters\.testOptionsCaller formatters/default_test\.go:\d+`,
+		string(b), "file column should contain function and module relative path")
+}
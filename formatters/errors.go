@@ -0,0 +1,131 @@
+package formatters
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Errors wrapped deeper are left out of ErrorInfo.
+const maxErrorDepth int = 32
+
+// Structured representation of error.
+// JSONFormatter writes fields of ErrorKind in this form.
+type ErrorInfo struct {
+	// Result of Error method.
+	Message string `json:"message"`
+	// Concrete type of error, e.g. "*fs.PathError".
+	Type string `json:"type"`
+	// Errors returned by Unwrap() error or Unwrap() []error method.
+	Causes []*ErrorInfo `json:"causes,omitempty"`
+	// Stack trace returned by StackTrace method of error (e.g. github.com/pkg/errors).
+	Stack []Frame `json:"stack,omitempty"`
+}
+
+// Returns ErrorInfo of err with its wrapped errors.
+// Returns nil if err is nil.
+func NewErrorInfo(err error) *ErrorInfo {
+	return newErrorInfo(err, 0)
+}
+
+func newErrorInfo(err error, depth int) *ErrorInfo {
+	if err == nil {
+		return nil
+	}
+
+	info := &ErrorInfo{Message: errorMessage(err), Type: fmt.Sprintf("%T", err)}
+
+	// Unwrap and StackTrace methods may panic on nil pointer too.
+	if v := reflect.ValueOf(err); v.Kind() == reflect.Pointer && v.IsNil() {
+		return info
+	}
+
+	info.Stack = errorStack(err)
+
+	if depth >= maxErrorDepth {
+		return info
+	}
+
+	var causes []error
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		causes = []error{e.Unwrap()}
+	case interface{ Unwrap() []error }:
+		causes = e.Unwrap()
+	}
+
+	for _, cause := range causes {
+		if cause := newErrorInfo(cause, depth+1); cause != nil {
+			info.Causes = append(info.Causes, cause)
+		}
+	}
+
+	return info
+}
+
+// Returns types of error and its wrapped errors in form of "*fmt.wrapError > *fs.PathError".
+// Errors joined with errors.Join are listed in brackets: "*errors.joinError[*errors.errorString, *fs.PathError]".
+func (ei *ErrorInfo) TypeChain() string {
+	var b strings.Builder
+	ei.writeTypeChain(&b)
+
+	return b.String()
+}
+
+func (ei *ErrorInfo) writeTypeChain(b *strings.Builder) {
+	b.WriteString(ei.Type)
+
+	switch len(ei.Causes) {
+	case 0:
+	case 1:
+		b.WriteString(" > ")
+		ei.Causes[0].writeTypeChain(b)
+	default:
+		b.WriteByte('[')
+		for i, cause := range ei.Causes {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+
+			cause.writeTypeChain(b)
+		}
+		b.WriteByte(']')
+	}
+}
+
+// Returns stack trace of err if it has StackTrace method that returns slice of program counters
+// as returned by runtime.Callers, like errors.StackTrace of github.com/pkg/errors.
+func errorStack(err error) []Frame {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return nil
+	}
+
+	out := method.Type().Out(0)
+	if out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	trace := method.Call(nil)[0]
+	pcs := make([]uintptr, trace.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(trace.Index(i).Uint())
+	}
+
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	frames := runtime.CallersFrames(pcs)
+
+	var stack []Frame
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+
+		if !more {
+			return stack
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package formatters
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Key of Field that carries name of a Logger.
+// JSONFormatter writes it to Log.Logger instead of Log.Tags.
+const LoggerKey = "logger"
+
+// Keys of Fields that carry OpenTelemetry trace ID, span ID and trace flags.
+// JSONFormatter writes them to Log.TraceID, Log.SpanID and Log.TraceFlags instead of Log.Tags.
+const (
+	TraceIDKey    = "trace_id"
+	SpanIDKey     = "span_id"
+	TraceFlagsKey = "trace_flags"
+)
+
+// Key of Field that carries time of log entry.
+// Formatters use it instead of current time and do not print time if it is zero.
+const EntryTimeKey = "time"
+
+// Key of Field that carries stack trace of log entry.
+// JSONFormatter writes it to Log.Stack instead of Log.Tags.
+const StackKey = "stack"
+
+// Kind of value carried by Field.
+type FieldKind int
+
+const (
+	// Field carries string value.
+	StringKind FieldKind = iota
+	// Field carries []string value.
+	// Tags added with Logger.AddTag are of this kind.
+	StringsKind
+	// Field carries int64 value.
+	Int64Kind
+	// Field carries float64 value.
+	Float64Kind
+	// Field carries bool value.
+	BoolKind
+	// Field carries time.Time value.
+	TimeKind
+	// Field carries time.Duration value.
+	DurationKind
+	// Field carries error value.
+	ErrorKind
+	// Field carries arbitrary value.
+	// JSONFormatter marshals it with encoding/json.
+	ObjectKind
+	// Field carries []Frame value.
+	StackKind
+	// Field carries time.Time value of log entry.
+	EntryTimeKind
+)
+
+// Field is a typed key-value pair attached to log entry.
+type Field struct {
+	Key   string
+	Kind  FieldKind
+	Value interface{}
+}
+
+// Returns Field of StringKind.
+func String(key, value string) Field {
+	return Field{Key: key, Kind: StringKind, Value: value}
+}
+
+// Returns Field of StringsKind.
+func Strings(key string, value ...string) Field {
+	return Field{Key: key, Kind: StringsKind, Value: value}
+}
+
+// Returns Field of Int64Kind.
+func Int(key string, value int) Field {
+	return Int64(key, int64(value))
+}
+
+// Returns Field of Int64Kind.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Kind: Int64Kind, Value: value}
+}
+
+// Returns Field of Float64Kind.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Kind: Float64Kind, Value: value}
+}
+
+// Returns Field of BoolKind.
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Kind: BoolKind, Value: value}
+}
+
+// Returns Field of TimeKind.
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Kind: TimeKind, Value: value}
+}
+
+// Returns Field of DurationKind.
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Kind: DurationKind, Value: value}
+}
+
+// Returns Field of ErrorKind with "error" as a key.
+func Err(err error) Field {
+	return NamedErr("error", err)
+}
+
+// Returns Field of ErrorKind.
+func NamedErr(key string, err error) Field {
+	return Field{Key: key, Kind: ErrorKind, Value: err}
+}
+
+// Returns Field of ObjectKind.
+func Object(key string, value interface{}) Field {
+	return Field{Key: key, Kind: ObjectKind, Value: value}
+}
+
+// Returns Field of EntryTimeKind with EntryTimeKey as a key.
+func EntryTime(value time.Time) Field {
+	return Field{Key: EntryTimeKey, Kind: EntryTimeKind, Value: value}
+}
+
+// Returns Field of the most suitable kind for value.
+func Any(key string, value interface{}) Field {
+	switch v := value.(type) {
+	case string:
+		return String(key, v)
+	case []string:
+		return Strings(key, v...)
+	case int:
+		return Int(key, v)
+	case int8:
+		return Int64(key, int64(v))
+	case int16:
+		return Int64(key, int64(v))
+	case int32:
+		return Int64(key, int64(v))
+	case int64:
+		return Int64(key, v)
+	case uint8:
+		return Int64(key, int64(v))
+	case uint16:
+		return Int64(key, int64(v))
+	case uint32:
+		return Int64(key, int64(v))
+	case float32:
+		return Float64(key, float64(v))
+	case float64:
+		return Float64(key, v)
+	case bool:
+		return Bool(key, v)
+	case time.Time:
+		return Time(key, v)
+	case time.Duration:
+		return Duration(key, v)
+	case error:
+		return NamedErr(key, v)
+	default:
+		return Object(key, v)
+	}
+}
+
+// Returns Field value in form of plain text.
+func (f Field) Text() string {
+	switch f.Kind {
+	case StringKind:
+		return f.Value.(string)
+	case StringsKind:
+		return strings.Join(f.Value.([]string), ",")
+	case TimeKind, EntryTimeKind:
+		return f.Value.(time.Time).Format(time.RFC3339)
+	case ErrorKind:
+		if f.Value == nil {
+			return "<nil>"
+		}
+
+		return errorMessage(f.Value.(error))
+	case ObjectKind:
+		if s, ok := f.Value.(fmt.Stringer); ok {
+			return stringerText(s)
+		}
+
+		b, err := json.Marshal(f.Value)
+		if err != nil {
+			return fmt.Sprintf("%+v", f.Value)
+		}
+
+		return string(b)
+	case StackKind:
+		frames := f.Value.([]Frame)
+		rows := make([]string, len(frames))
+		for i, frame := range frames {
+			rows[i] = frame.String()
+		}
+
+		return strings.Join(rows, "\n")
+	default:
+		return fmt.Sprint(f.Value)
+	}
+}
+
+// Returns time of log entry carried by fields.
+// Returns current time if fields do not carry it.
+func entryTime(fields []Field) time.Time {
+	for _, field := range fields {
+		if field.isEntryTime() {
+			return field.Value.(time.Time)
+		}
+	}
+
+	return time.Now()
+}
+
+func (f Field) isEntryTime() bool {
+	return f.Key == EntryTimeKey && f.Kind == EntryTimeKind
+}
+
+// Returns result of Error method of err.
+// Returns "<nil>" the same way fmt does if err is nil pointer Error method panics on.
+func errorMessage(err error) string {
+	return methodText(err, "Error", err.Error)
+}
+
+// Returns result of String method of s.
+// Returns "<nil>" the same way fmt does if s is nil pointer String method panics on.
+func stringerText(s fmt.Stringer) string {
+	return methodText(s, "String", s.String)
+}
+
+// Returns result of method of v, recovering from panic the same way fmt does.
+func methodText(v interface{}, name string, method func() string) (text string) {
+	defer func() {
+		if r := recover(); r != nil {
+			if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+				text = "<nil>"
+				return
+			}
+
+			text = fmt.Sprintf("%%!v(PANIC=%s method: %v)", name, r)
+		}
+	}()
+
+	return method()
+}
+
+// Returns Field value in form encoding/json can always marshal.
+// Values JSON has no representation for are written as plain text.
+func (f Field) jsonValue() interface{} {
+	switch f.Kind {
+	case Float64Kind:
+		if v := f.Value.(float64); math.IsNaN(v) || math.IsInf(v, 0) {
+			return strconv.FormatFloat(v, 'g', -1, 64)
+		}
+
+		return f.Value
+	case ObjectKind:
+		b, err := json.Marshal(f.Value)
+		if err != nil {
+			return f.Text()
+		}
+
+		return json.RawMessage(b)
+	case DurationKind:
+		return f.Value.(time.Duration).String()
+	case ErrorKind:
+		if f.Value == nil {
+			return nil
+		}
+
+		return NewErrorInfo(f.Value.(error))
+	default:
+		return f.Value
+	}
+}
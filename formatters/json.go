@@ -10,26 +10,79 @@ import (
 // Formatter that returns log message in form of JSON.
 const JSONFormatter jsonFormatter = "JSONFormatter"
 
+// Configuration of JSON Formatter.
+type JSONOptions struct {
+	// Defines how path of caller file is written to Log.Location.
+	// BasePath is used by default.
+	PathMode PathMode
+}
+
+// Returns instance of JSON Formatter configured with opts.
+// Formatter that returns log message in form of JSON.
+func NewJSONFormatter(opts JSONOptions) LogFormatter {
+	return &configuredJSONFormatter{opts: opts}
+}
+
 type jsonFormatter string
 
-func (f jsonFormatter) GetOutput(level int, message string, tags map[string][]string, calldepth int) []byte {
-	now := time.Now().Round(time.Millisecond)
+func (f jsonFormatter) GetOutput(level int, message string, fields []Field, calldepth int) []byte {
+	return formatJSON(string(f), level, message, fields, callerAt(calldepth, BasePath))
+}
+
+type configuredJSONFormatter struct {
+	opts JSONOptions
+}
+
+func (f *configuredJSONFormatter) GetOutput(level int, message string, fields []Field, calldepth int) []byte {
+	return formatJSON(string(JSONFormatter), level, message, fields, callerAt(calldepth, f.opts.PathMode))
+}
+
+func formatJSON(formatter string, level int, message string, fields []Field, caller Caller) []byte {
+	now := entryTime(fields).Round(time.Millisecond)
 	levelS, _ := getLevelTextAndColor(level)
-	file, line := getFileAndLine(calldepth + 1)
 	message = DecolorizeString(message)
-
+	tags := make(map[string]interface{}, len(fields))
 	m := Log{
 		LevelCode: level,
 		Level:     strings.TrimLeft(levelS, " "),
-		Location:  fmt.Sprintf("%v:%d", file, line),
+		Location:  caller.Location(),
+		Function:  caller.Function,
 		Message:   message,
 		DateUnix:  now,
 		Tags:      tags}
 
-	b, err := json.Marshal(m)
+	for _, field := range fields {
+		switch {
+		case field.Key == LoggerKey:
+			m.Logger = field.Text()
+		case field.Key == TraceIDKey:
+			m.TraceID = field.Text()
+		case field.Key == SpanIDKey:
+			m.SpanID = field.Text()
+		case field.Key == TraceFlagsKey:
+			m.TraceFlags = field.Text()
+		case field.Key == StackKey && field.Kind == StackKind:
+			m.Stack = field.Value.([]Frame)
+		case field.isEntryTime():
+			// written to Log.DateUnix.
+		default:
+			tags[field.Key] = field.jsonValue()
+		}
+	}
+
+	var v interface{} = m
+	if now.IsZero() {
+		// date of embedded Log is hidden by nil one.
+		v = struct {
+			Log
+			DateUnix *time.Time `json:"date,omitempty"`
+		}{Log: m}
+	}
+
+	b, err := json.Marshal(v)
 
 	if err != nil {
-		fmt.Printf(PaintText(ANSIColorRed, fmt.Sprintf("%s: failed to write log: %s", f, err)))
+		fmt.Printf(PaintText(ANSIColorRed, fmt.Sprintf("%s: failed to write log: %s", formatter, err)))
 
 		return []byte("")
 	}
@@ -2,6 +2,10 @@ package formatters
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
+	"regexp"
+	"runtime"
 	"testing"
 	"time"
 
@@ -10,13 +14,15 @@ import (
 
 func TestJSONLoggerFormatter(t *testing.T) {
 	t.Run("GetOutput returns correct JSON of formatter.Log model", testJSONFormatterOutput)
+	t.Run("GetOutput returns typed fields as native JSON values", testJSONFormatterTypedFields)
+	t.Run("NewJSONFormatter configures path of caller file", testJSONFormatterPathMode)
+	t.Run("GetOutput writes fields JSON has no representation for as text", testJSONFormatterUnsupportedValues)
 }
 
 func testJSONFormatterOutput(t *testing.T) {
 	assert := assert.New(t)
 
-	tags := make(map[string][]string)
-	tags["tag"] = []string{"cool tag"}
+	tags := []Field{Strings("tag", "cool tag")}
 	now := time.Now().Round(time.Millisecond)
 	b := JSONFormatter.GetOutput(2, "test json", tags, 0)
 	m := new(Log)
@@ -25,13 +31,93 @@ func testJSONFormatterOutput(t *testing.T) {
 		assert.FailNow("got wrong log format")
 	}
 
+	assert.True(now.Equal(m.DateUnix), "log should contain correct date")
+
 	expected := Log{
 		LevelCode: 2,
 		Level:     "INFO",
-		Location:  "json_test.go:21",
+		Location:  "json_test.go:27",
+		Function:  "github.com/andriiyaremenko/tinylog/formatters.testJSONFormatterOutput",
 		Message:   "test json",
-		Tags:      tags,
-		DateUnix:  now}
+		Tags:      map[string]interface{}{"tag": []interface{}{"cool tag"}},
+		DateUnix:  m.DateUnix}
 
 	assert.EqualValues(expected, *m, "log should contain all fields with correct values")
 }
+
+func testJSONFormatterTypedFields(t *testing.T) {
+	assert := assert.New(t)
+
+	b := JSONFormatter.GetOutput(2, "test json", []Field{
+		Int("int", 42),
+		Float64("float", 0.5),
+		Bool("bool", true),
+		Duration("duration", time.Second),
+		Err(errors.New("oops")),
+		Object("object", struct {
+			Name string `json:"name"`
+		}{"tiny"}),
+	}, 0)
+	m := new(Log)
+
+	if err := json.Unmarshal(b, m); err != nil {
+		assert.FailNow("got wrong log format")
+	}
+
+	assert.Equal(float64(42), m.Tags["int"], "int field should be JSON number")
+	assert.Equal(0.5, m.Tags["float"], "float field should be JSON number")
+	assert.Equal(true, m.Tags["bool"], "bool field should be JSON boolean")
+	assert.Equal("1s", m.Tags["duration"], "duration field should be JSON string")
+	assert.Equal(map[string]interface{}{"message": "oops", "type": "*errors.errorString"}, m.Tags["error"],
+		"error field should be JSON object")
+	assert.Equal(map[string]interface{}{"name": "tiny"}, m.Tags["object"], "object field should be JSON object")
+}
+
+func testJSONFormatterPathMode(t *testing.T) {
+	assert := assert.New(t)
+	_, file, _, _ := runtime.Caller(0)
+
+	for mode, expected := range map[PathMode]string{
+		BasePath:     "json_test.go",
+		PackagePath:  "github.com/andriiyaremenko/tinylog/formatters/json_test.go",
+		ModulePath:   "formatters/json_test.go",
+		AbsolutePath: file,
+	} {
+		b := NewJSONFormatter(JSONOptions{PathMode: mode}).GetOutput(2, "test json", nil, 0)
+		m := new(Log)
+
+		if err := json.Unmarshal(b, m); err != nil {
+			assert.FailNow("got wrong log format")
+		}
+
+		assert.Regexp(`^`+regexp.QuoteMeta(expected)+`:\d+$`, m.Location, "location should use path mode")
+		assert.Equal("github.com/andriiyaremenko/tinylog/formatters.testJSONFormatterPathMode", m.Function,
+			"function should be printed")
+	}
+}
+
+func testJSONFormatterUnsupportedValues(t *testing.T) {
+	assert := assert.New(t)
+
+	b := JSONFormatter.GetOutput(2, "test json", []Field{
+		Float64("nan", math.NaN()),
+		Float64("inf", math.Inf(1)),
+		Float64("-inf", math.Inf(-1)),
+		Object("func", func() {}),
+		Any("chan", make(chan int)),
+		Object("object", map[string]float64{"nan": math.NaN()}),
+	}, 0)
+	m := new(Log)
+
+	if err := json.Unmarshal(b, m); err != nil {
+		assert.FailNow("log entry should be written")
+	}
+
+	assert.Equal("test json", m.Message, "log entry should be written")
+	assert.Equal("NaN", m.Tags["nan"], "NaN should be JSON string")
+	assert.Equal("+Inf", m.Tags["inf"], "+Inf should be JSON string")
+	assert.Equal("-Inf", m.Tags["-inf"], "-Inf should be JSON string")
+	assert.Regexp(`^0x[0-9a-f]+$`, m.Tags["func"], "func should be written as text")
+	assert.Regexp(`^0x[0-9a-f]+$`, m.Tags["chan"], "chan should be written as text")
+	assert.Equal("map[nan:NaN]", m.Tags["object"], "object that can not be marshaled should be written as text")
+}
@@ -0,0 +1,91 @@
+package formatters
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Formatter that returns log message in form of logfmt line:
+// time=2021-07-01T12:00:00.000+03:00 level=info msg="hello logger" caller=main.go:12 key=value
+const Logfmt logfmtFormatter = "Logfmt"
+
+const logfmtTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+type logfmtFormatter string
+
+func (f logfmtFormatter) GetOutput(level int, message string, fields []Field, calldepth int) []byte {
+	now := entryTime(fields)
+	levelS, _ := getLevelTextAndColor(level)
+	caller := callerAt(calldepth, BasePath)
+
+	var b strings.Builder
+
+	if !now.IsZero() {
+		writeLogfmtPair(&b, "time", now.Format(logfmtTimeFormat))
+	}
+
+	writeLogfmtPair(&b, "level", strings.ToLower(strings.TrimLeft(levelS, " ")))
+	writeLogfmtPair(&b, "msg", DecolorizeString(message))
+
+	if location := caller.Location(); location != "" {
+		writeLogfmtPair(&b, "caller", location)
+	}
+
+	for _, field := range fields {
+		if field.isEntryTime() {
+			continue
+		}
+
+		writeLogfmtPair(&b, field.Key, DecolorizeString(field.Text()))
+	}
+
+	b.WriteByte('\n')
+
+	return []byte(b.String())
+}
+
+func writeLogfmtPair(b *strings.Builder, key, value string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+
+	b.WriteString(logfmtKey(key))
+	b.WriteByte('=')
+
+	if logfmtNeedsQuoting(value) {
+		b.WriteString(strconv.Quote(value))
+		return
+	}
+
+	b.WriteString(value)
+}
+
+// Replaces characters that are not allowed in logfmt key with '_'.
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || !unicode.IsPrint(r) {
+			return '_'
+		}
+
+		return r
+	}, key)
+}
+
+func logfmtNeedsQuoting(value string) bool {
+	if value == "" {
+		return true
+	}
+
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,59 @@
+package formatters
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogfmtFormatter(t *testing.T) {
+	t.Run("GetOutput returns logfmt line", testLogfmtOutput)
+	t.Run("GetOutput quotes and escapes values", testLogfmtQuoting)
+	t.Run("GetOutput writes time of entry and omits zero time", testLogfmtEntryTime)
+}
+
+func testLogfmtOutput(t *testing.T) {
+	assert := assert.New(t)
+	b := Logfmt.GetOutput(3, PaintText(ANSIColorBlue, "disk"), []Field{Int("free", 42), Bool("critical", false)}, 0)
+	s := string(b)
+
+	assert.True(strings.HasPrefix(s, "time="), "line should start with time")
+	assert.True(strings.HasSuffix(s, "\n"), "line should end with new line")
+	assert.Contains(s, " level=warn msg=disk caller=logfmt_test.go:19 free=42 critical=false\n",
+		"line should contain level, message, caller and fields in order")
+}
+
+func testLogfmtQuoting(t *testing.T) {
+	assert := assert.New(t)
+	b := Logfmt.GetOutput(2, "hello logger", []Field{
+		String("empty", ""),
+		String("quote", `say "hi"`),
+		String("multi line", "a\nb"),
+		String("eq", "a=b"),
+		Strings("list", "me", "cat"),
+	}, 0)
+	s := string(b)
+
+	assert.Contains(s, `msg="hello logger"`, "message with spaces should be quoted")
+	assert.Contains(s, `empty=""`, "empty value should be quoted")
+	assert.Contains(s, `quote="say \"hi\""`, "quotes should be escaped")
+	assert.Contains(s, `multi_line="a\nb"`, "new lines should be escaped, spaces in key replaced")
+	assert.Contains(s, `eq="a=b"`, "value with = should be quoted")
+	assert.Contains(s, `list=me,cat`, "strings field should be joined")
+}
+
+func testLogfmtEntryTime(t *testing.T) {
+	assert := assert.New(t)
+	date := time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	s := string(Logfmt.GetOutput(2, "hello", []Field{EntryTime(date), Int("id", 42)}, -1))
+
+	assert.Equal("time=2021-07-01T12:00:00.000Z level=info msg=hello id=42\n", s,
+		"time of entry should be written instead of current time")
+
+	s = string(Logfmt.GetOutput(2, "hello", []Field{EntryTime(time.Time{})}, -1))
+
+	assert.Equal("level=info msg=hello\n", s, "zero time should be omitted")
+}
@@ -7,10 +7,16 @@ import (
 
 // Log model returned by JSONFormatter.
 type Log struct {
-	LevelCode int                 `json:"levelCode"`
-	Level     string              `json:"level"`
-	Location  string              `json:"location"`
-	Message   string              `json:"message"`
-	Tags      map[string][]string `json:"tags"`
-	DateUnix  time.Time           `json:"date"`
+	LevelCode  int                    `json:"levelCode"`
+	Level      string                 `json:"level"`
+	Location   string                 `json:"location"`
+	Function   string                 `json:"function,omitempty"`
+	Logger     string                 `json:"logger,omitempty"`
+	TraceID    string                 `json:"trace_id,omitempty"`
+	SpanID     string                 `json:"span_id,omitempty"`
+	TraceFlags string                 `json:"trace_flags,omitempty"`
+	Message    string                 `json:"message"`
+	Tags       map[string]interface{} `json:"tags"`
+	Stack      []Frame                `json:"stack,omitempty"`
+	DateUnix   time.Time              `json:"date"`
 }
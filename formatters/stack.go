@@ -0,0 +1,20 @@
+package formatters
+
+import "fmt"
+
+// Frame of stack trace.
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// Returns frame in form of "function (file:line)".
+func (f Frame) String() string {
+	return fmt.Sprintf("%s (%s:%d)", f.Function, f.File, f.Line)
+}
+
+// Returns Field of StackKind with StackKey as a key.
+func Stack(frames []Frame) Field {
+	return Field{Key: StackKey, Kind: StackKind, Value: frames}
+}
@@ -0,0 +1,57 @@
+package formatters
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+type fdWriter interface {
+	Fd() uintptr
+}
+
+// Returns width of terminal out is attached to.
+// Returns false if out is not a terminal.
+func terminalWidth(out io.Writer) (int, bool) {
+	f, ok := out.(fdWriter)
+	if !ok {
+		return 0, false
+	}
+
+	width, _, err := term.GetSize(int(f.Fd()))
+	if err != nil || width <= 0 {
+		return 0, false
+	}
+
+	return width, true
+}
+
+// Reports if out is a terminal.
+func isTerminal(out io.Writer) bool {
+	f, ok := out.(fdWriter)
+
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// Reports if output should be colorized according to mode, FORCE_COLOR and NO_COLOR environment variables
+// and whether out is a terminal.
+// Output to unknown (nil) out is colorized unless environment variables say otherwise.
+func shouldColorize(mode ColorMode, out io.Writer) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if force := os.Getenv("FORCE_COLOR"); force != "" {
+		return force != "0" && force != "false"
+	}
+
+	if noColor := os.Getenv("NO_COLOR"); noColor != "" {
+		return false
+	}
+
+	return out == nil || isTerminal(out)
+}
@@ -0,0 +1,100 @@
+package formatters
+
+import "fmt"
+
+// Returns color from 256-color palette.
+func Color256(n uint8) Color {
+	return Color(fmt.Sprintf("\033[38;5;%dm", n))
+}
+
+// Returns 24-bit RGB color.
+func TrueColor(r, g, b uint8) Color {
+	return Color(fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b))
+}
+
+// Colors used by default Formatter.
+// Empty Color means text is printed as is.
+type Theme struct {
+	// Colors of level column.
+	// TRACE and FATAL messages are printed in Trace and Fatal colors as well.
+	Trace Color
+	Debug Color
+	Info  Color
+	Warn  Color
+	Error Color
+	Fatal Color
+	// Color of date column.
+	Date Color
+	// Color of file column.
+	File Color
+	// Color of tags column.
+	// Empty = color of level.
+	Tags Color
+}
+
+// Returns Theme for terminals with dark background.
+// Used by default Formatter if no Theme is provided.
+func DarkTheme() Theme {
+	return Theme{
+		Trace: ColorTrace,
+		Debug: ColorDebug,
+		Info:  ColorInfo,
+		Warn:  ColorWarn,
+		Error: ColorError,
+		Fatal: ColorFatal,
+		Date:  ANSIColorGray,
+		File:  ANSIColorGray,
+	}
+}
+
+// Returns Theme for terminals with light background.
+func LightTheme() Theme {
+	return Theme{
+		Trace: Color256(244),
+		Debug: ANSIColorBlue,
+		Info:  Color256(28),
+		Warn:  Color256(130),
+		Error: ANSIColorRed,
+		Fatal: ANSIFontBold + ANSIColorRed,
+		Date:  Color256(240),
+		File:  Color256(240),
+	}
+}
+
+// Returns Theme that uses only font weight instead of colors.
+func MonochromeTheme() Theme {
+	return Theme{
+		Trace: ANSIFontFaint,
+		Error: ANSIFontBold,
+		Fatal: ANSIFontBold,
+		Date:  ANSIFontFaint,
+		File:  ANSIFontFaint,
+	}
+}
+
+func (t Theme) levelColor(level int) Color {
+	switch level {
+	case 0:
+		return t.Trace
+	case 1:
+		return t.Debug
+	case 2:
+		return t.Info
+	case 3:
+		return t.Warn
+	case 4:
+		return t.Error
+	case 5:
+		return t.Fatal
+	}
+
+	return ""
+}
+
+func (t Theme) tagsColor(level int) Color {
+	if t.Tags != "" {
+		return t.Tags
+	}
+
+	return t.levelColor(level)
+}
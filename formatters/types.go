@@ -1,7 +1,19 @@
 package formatters
 
+import "io"
+
 // Carries log message formatting and marshalling logic.
 type LogFormatter interface {
 	// Returns formatted log message in []byte.
-	GetOutput(level int, message string, tags map[string][]string, calldepth int) []byte
+	// calldepth is number of stack frames to skip to get to the caller of logging function
+	// relative to the caller of GetOutput.
+	// Caller is not looked up if calldepth is negative.
+	GetOutput(level int, message string, fields []Field, calldepth int) []byte
+}
+
+// Implemented by LogFormatter which output depends on destination it writes to.
+type WriterFormatter interface {
+	LogFormatter
+	// Returns LogFormatter that writes to out.
+	ForWriter(out io.Writer) LogFormatter
 }
@@ -2,7 +2,6 @@ package formatters
 
 import (
 	"regexp"
-	"runtime"
 	"unicode/utf8"
 )
 
@@ -19,6 +18,7 @@ const (
 	ANSIColorWhite  Color = "\033[37m"
 	ANSIColorGray   Color = "\033[90m"
 	ANSIFontBold    Color = "\033[1m"
+	ANSIFontFaint   Color = "\033[2m"
 
 	ColorTrace Color = ANSIColorGray
 	ColorDebug Color = ANSIColorCyan
@@ -86,23 +86,3 @@ func getLevelTextAndColor(level int) (string, Color) {
 
 	return levelS, color
 }
-
-func getFileAndLine(calldepth int) (string, int) {
-	_, file, line, ok := runtime.Caller(calldepth + 1)
-
-	if !ok {
-		file = "???"
-		line = 0
-	}
-
-	short := file
-
-	for i := len(file) - 1; i > 0; i-- {
-		if file[i] == '/' {
-			short = file[i+1:]
-			break
-		}
-	}
-
-	return short, line
-}
@@ -1,5 +1,17 @@
 module github.com/andriiyaremenko/tinylog
 
-go 1.14
+go 1.21
 
-require github.com/stretchr/testify v1.6.1
+require (
+	github.com/stretchr/testify v1.8.4
+	go.opentelemetry.io/otel/trace v1.24.0
+	golang.org/x/term v0.27.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.opentelemetry.io/otel v1.24.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
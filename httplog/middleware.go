@@ -0,0 +1,185 @@
+// Package httplog provides net/http middleware that binds request-scoped Logger to every request.
+package httplog
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/andriiyaremenko/tinylog"
+)
+
+const defaultRequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// Configuration of Middleware.
+type Options struct {
+	// Header request ID is read from and written to response.
+	// Empty = "X-Request-ID".
+	RequestIDHeader string
+	// Generates request ID for request without one.
+	// nil = 16 random bytes in hex.
+	GenerateRequestID func() string
+}
+
+// Returns middleware that gets Logger from lf for every request,
+// tags it with method, path, remote_addr and request_id fields and stores it in request context,
+// so it is available with tinylog.FromContext and lf.GetLogger.
+// Request ID is taken from request header or generated and is written to response header.
+// Once request is handled status, bytes and latency are logged with level chosen by status:
+// Info for 1xx-3xx, Warn for 4xx and Error for 5xx.
+func Middleware(lf tinylog.LoggerFactory, opts Options) func(http.Handler) http.Handler {
+	if opts.RequestIDHeader == "" {
+		opts.RequestIDHeader = defaultRequestIDHeader
+	}
+
+	if opts.GenerateRequestID == nil {
+		opts.GenerateRequestID = generateRequestID
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestID := r.Header.Get(opts.RequestIDHeader)
+			if requestID == "" {
+				requestID = opts.GenerateRequestID()
+			}
+
+			ctx := context.WithValue(r.Context(), requestIDKey{}, requestID)
+			// With keeps Logger carried by request context untouched if lf falls back to it.
+			l := lf.GetLogger(ctx).With(
+				tinylog.String("method", r.Method),
+				tinylog.String("path", r.URL.Path),
+				tinylog.String("remote_addr", r.RemoteAddr),
+				tinylog.String("request_id", requestID))
+
+			loggerCtx := tinylog.NewContext(ctx, l)
+			// request context is not always cancelled, e.g. in tests.
+			defer lf.Release(ctx)
+			defer lf.Release(loggerCtx)
+
+			w.Header().Set(opts.RequestIDHeader, requestID)
+
+			rw := &responseWriter{ResponseWriter: w}
+			next.ServeHTTP(rw, r.WithContext(loggerCtx))
+
+			l.Printw(levelOf(rw.status()), "request completed",
+				tinylog.Int("status", rw.status()),
+				tinylog.Int64("bytes", rw.bytes),
+				tinylog.Duration("latency", time.Since(start)))
+		})
+	}
+}
+
+// Returns request ID stored in ctx by Middleware.
+// Returns false if there is none.
+func RequestID(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey{}).(string)
+	return requestID, ok
+}
+
+// tinylog.ContextExtractor that returns request_id field with request ID stored in ctx by Middleware.
+func RequestIDExtractor(ctx context.Context) []tinylog.Field {
+	if requestID, ok := RequestID(ctx); ok {
+		return []tinylog.Field{tinylog.String("request_id", requestID)}
+	}
+
+	return nil
+}
+
+func levelOf(status int) int {
+	switch {
+	case status >= 500:
+		return tinylog.Error
+	case status >= 400:
+		return tinylog.Warn
+	default:
+		return tinylog.Info
+	}
+}
+
+func generateRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// responseWriter records status and number of bytes written.
+type responseWriter struct {
+	http.ResponseWriter
+
+	code  int
+	bytes int64
+}
+
+// Informational 1xx statuses other than 101 Switching Protocols are not recorded,
+// they are followed by the final one.
+func (rw *responseWriter) WriteHeader(code int) {
+	informational := code >= 100 && code <= 199 && code != http.StatusSwitchingProtocols
+	if rw.code == 0 && !informational {
+		rw.code = code
+	}
+
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *responseWriter) Write(p []byte) (int, error) {
+	if rw.code == 0 {
+		rw.code = http.StatusOK
+	}
+
+	n, err := rw.ResponseWriter.Write(p)
+	rw.bytes += int64(n)
+
+	return n, err
+}
+
+// Flushes wrapped http.ResponseWriter if it implements http.Flusher.
+func (rw *responseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		if rw.code == 0 {
+			rw.code = http.StatusOK
+		}
+
+		f.Flush()
+	}
+}
+
+// Hijacks connection of wrapped http.ResponseWriter if it implements http.Hijacker.
+// Hijacked connection is recorded with 101 Switching Protocols status.
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T does not implement http.Hijacker: %w", rw.ResponseWriter, http.ErrNotSupported)
+	}
+
+	conn, buf, err := h.Hijack()
+	if err == nil && rw.code == 0 {
+		rw.code = http.StatusSwitchingProtocols
+	}
+
+	return conn, buf, err
+}
+
+// Returns wrapped http.ResponseWriter for http.ResponseController.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+func (rw *responseWriter) status() int {
+	if rw.code == 0 {
+		return http.StatusOK
+	}
+
+	return rw.code
+}
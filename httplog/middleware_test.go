@@ -0,0 +1,182 @@
+package httplog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andriiyaremenko/tinylog"
+	"github.com/andriiyaremenko/tinylog/formatters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Run("Middleware logs completed request with level chosen by status", testMiddlewareLevels)
+	t.Run("Middleware stores request-scoped Logger in request context", testMiddlewareLogger)
+	t.Run("Middleware propagates or generates request ID", testMiddlewareRequestID)
+	t.Run("Middleware logs final status after informational one", testMiddlewareInformationalStatus)
+	t.Run("Middleware forwards http.Hijacker", testMiddlewareHijack)
+}
+
+func serve(lf tinylog.LoggerFactory, r *http.Request, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	Middleware(lf, Options{})(handler).ServeHTTP(w, r)
+
+	return w
+}
+
+func decodeLog(assert *assert.Assertions, dec *json.Decoder) *formatters.Log {
+	m := new(formatters.Log)
+	if err := dec.Decode(m); err != nil {
+		assert.FailNow("got wrong log format")
+	}
+
+	return m
+}
+
+func testMiddlewareLevels(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	lf := tinylog.NewLoggerFactory(tinylog.DestinationFunc(b, formatters.JSONFormatter, tinylog.Info))
+	dec := json.NewDecoder(b)
+
+	for status, level := range map[int]string{
+		http.StatusOK:                  "INFO",
+		http.StatusFound:               "INFO",
+		http.StatusNotFound:            "WARN",
+		http.StatusInternalServerError: "ERROR",
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/users?id=42", nil)
+		serve(lf, r, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			w.Write([]byte("hello"))
+		})
+
+		m := decodeLog(assert, dec)
+
+		assert.Equalf(level, m.Level, "level should be chosen by status %d", status)
+		assert.Equal("request completed", m.Message)
+		assert.Equal(float64(status), m.Tags["status"], "status should be printed")
+		assert.Equal(float64(5), m.Tags["bytes"], "number of bytes should be printed")
+		assert.NotEmpty(m.Tags["latency"], "latency should be printed")
+		assert.Equal("POST", m.Tags["method"], "method should be printed")
+		assert.Equal("/users", m.Tags["path"], "path should be printed")
+		assert.Equal(r.RemoteAddr, m.Tags["remote_addr"], "remote address should be printed")
+	}
+
+	assert.Equal(0, lf.Stats().LiveLoggers, "request-scoped Loggers should be released")
+}
+
+func testMiddlewareLogger(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	lf := tinylog.NewLoggerFactory(tinylog.DestinationFunc(b, formatters.JSONFormatter, tinylog.Info))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Request-ID", "42")
+
+	serve(lf, r, func(w http.ResponseWriter, r *http.Request) {
+		l, ok := tinylog.FromContext(r.Context())
+		if assert.True(ok, "Logger should be stored in request context") {
+			l.Println(tinylog.Info, "from context")
+		}
+
+		lf.GetLogger(r.Context()).Println(tinylog.Info, "from factory")
+	})
+
+	dec := json.NewDecoder(b)
+	for _, message := range []string{"from context", "from factory", "request completed"} {
+		m := decodeLog(assert, dec)
+
+		assert.Equal(message, m.Message)
+		assert.Equal("42", m.Tags["request_id"], "request ID should be printed")
+		assert.Equal("GET", m.Tags["method"], "method should be printed")
+	}
+
+	assert.Equal(0, lf.Stats().LiveLoggers, "request-scoped Loggers should be released")
+}
+
+func testMiddlewareRequestID(t *testing.T) {
+	assert := assert.New(t)
+	lf := tinylog.NewLoggerFactory(tinylog.DestinationFunc(new(bytes.Buffer), formatters.JSONFormatter, tinylog.Info))
+
+	var found string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		found, _ = RequestID(r.Context())
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Request-ID", "42")
+	w := serve(lf, r, handler)
+
+	assert.Equal("42", found, "request ID should be propagated")
+	assert.Equal("42", w.Header().Get("X-Request-ID"), "request ID should be written to response")
+
+	w = serve(lf, httptest.NewRequest(http.MethodGet, "/", nil), handler)
+
+	assert.Len(found, 32, "request ID should be generated")
+	assert.Equal(found, w.Header().Get("X-Request-ID"), "request ID should be written to response")
+	assert.Equal([]tinylog.Field{tinylog.String("request_id", found)},
+		RequestIDExtractor(context.WithValue(context.TODO(), requestIDKey{}, found)))
+}
+
+func testMiddlewareInformationalStatus(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	lf := tinylog.NewLoggerFactory(tinylog.DestinationFunc(b, formatters.JSONFormatter, tinylog.Info))
+
+	serve(lf, httptest.NewRequest(http.MethodGet, "/", nil), func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusEarlyHints)
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	m := decodeLog(assert, json.NewDecoder(b))
+
+	assert.Equal(float64(http.StatusNotFound), m.Tags["status"], "final status should be printed")
+}
+
+// hijackRecorder is httptest.ResponseRecorder that implements http.Hijacker.
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	conn net.Conn
+}
+
+func (hr *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return hr.conn, bufio.NewReadWriter(bufio.NewReader(hr.conn), bufio.NewWriter(hr.conn)), nil
+}
+
+func testMiddlewareHijack(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	lf := tinylog.NewLoggerFactory(tinylog.DestinationFunc(b, formatters.JSONFormatter, tinylog.Info))
+	conn, peer := net.Pipe()
+	defer peer.Close()
+
+	w := &hijackRecorder{ResponseRecorder: httptest.NewRecorder(), conn: conn}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		h, ok := w.(http.Hijacker)
+		if !assert.True(ok, "http.Hijacker should be forwarded") {
+			return
+		}
+
+		hijacked, _, err := h.Hijack()
+		assert.NoError(err)
+		assert.Equal(conn, hijacked, "connection of wrapped http.ResponseWriter should be returned")
+		hijacked.Close()
+	}
+
+	Middleware(lf, Options{})(http.HandlerFunc(handler)).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	m := decodeLog(assert, json.NewDecoder(b))
+
+	assert.Equal(float64(http.StatusSwitchingProtocols), m.Tags["status"], "hijacked connection should be printed")
+
+	serve(lf, httptest.NewRequest(http.MethodGet, "/", nil), func(w http.ResponseWriter, r *http.Request) {
+		_, _, err := w.(http.Hijacker).Hijack()
+		assert.ErrorIs(err, http.ErrNotSupported, "Hijack should fail if wrapped http.ResponseWriter does not support it")
+	})
+}
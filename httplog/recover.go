@@ -0,0 +1,48 @@
+package httplog
+
+import (
+	"net/http"
+
+	"github.com/andriiyaremenko/tinylog"
+)
+
+// Returns middleware that recovers panics of next handler and logs them with tinylog.LogPanic
+// using Logger stored in request context by Middleware or Logger from lf.
+// Responds with 500 Internal Server Error unless opts.Repanic is set or response was already written.
+// http.ErrAbortHandler is panicked again without being logged.
+func Recoverer(lf tinylog.LoggerFactory, opts tinylog.RecoverOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := &responseWriter{ResponseWriter: w}
+
+			defer func() {
+				value := recover()
+				if value == nil {
+					return
+				}
+
+				if value == http.ErrAbortHandler {
+					panic(value)
+				}
+
+				l, ok := tinylog.FromContext(r.Context())
+				if !ok {
+					l = lf.GetLogger(r.Context())
+					defer lf.Release(r.Context())
+				}
+
+				tinylog.LogPanic(l, value, opts)
+
+				if opts.Repanic {
+					panic(value)
+				}
+
+				if rw.code == 0 {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
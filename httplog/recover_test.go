@@ -0,0 +1,92 @@
+package httplog
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andriiyaremenko/tinylog"
+	"github.com/andriiyaremenko/tinylog/formatters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecoverer(t *testing.T) {
+	t.Run("Recoverer logs panic and responds with 500", testRecoverer)
+	t.Run("Recoverer panics again with ErrAbortHandler", testRecovererAbortHandler)
+	t.Run("Recoverer does not respond with 500 if response was written", testRecovererAfterResponse)
+}
+
+func testRecoverer(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	lf := tinylog.NewLoggerFactory(tinylog.DestinationFunc(b, formatters.JSONFormatter, tinylog.Info))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Request-ID", "42")
+
+	w := serve(lf, r, func(w http.ResponseWriter, r *http.Request) {
+		Recoverer(lf, tinylog.RecoverOptions{})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			panic("oops")
+		})).ServeHTTP(w, r)
+	})
+
+	assert.Equal(http.StatusInternalServerError, w.Code, "should respond with 500")
+
+	dec := json.NewDecoder(b)
+	m := decodeLog(assert, dec)
+
+	assert.Equal("ERROR", m.Level, "panic should be logged with Error level")
+	assert.Equal("panic: oops", m.Message, "panic should be logged")
+	assert.Equal("42", m.Tags["request_id"], "request-scoped Logger should be used")
+	assert.Contains(m.Location, "recover_test.go", "place of panic should be printed")
+	assert.NotEmpty(m.Stack, "stack trace should be printed")
+
+	m = decodeLog(assert, dec)
+
+	assert.Equal("request completed", m.Message)
+	assert.Equal(float64(http.StatusInternalServerError), m.Tags["status"], "status should be printed")
+	assert.Equal(0, lf.Stats().LiveLoggers, "request-scoped Loggers should be released")
+}
+
+func testRecovererAbortHandler(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	lf := tinylog.NewLoggerFactory(tinylog.DestinationFunc(b, formatters.JSONFormatter, tinylog.Info))
+	handler := Recoverer(lf, tinylog.RecoverOptions{})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	assert.PanicsWithValue(http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}, "ErrAbortHandler should be panicked again")
+	assert.Empty(b.String(), "ErrAbortHandler should not be logged")
+}
+
+// headerCounter counts WriteHeader calls.
+type headerCounter struct {
+	*httptest.ResponseRecorder
+	calls int
+}
+
+func (hc *headerCounter) WriteHeader(code int) {
+	hc.calls++
+	hc.ResponseRecorder.WriteHeader(code)
+}
+
+func testRecovererAfterResponse(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	lf := tinylog.NewLoggerFactory(tinylog.DestinationFunc(b, formatters.JSONFormatter, tinylog.Info))
+	w := &headerCounter{ResponseRecorder: httptest.NewRecorder()}
+	handler := Recoverer(lf, tinylog.RecoverOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		panic("oops")
+	}))
+
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(http.StatusAccepted, w.Code, "written status should be kept")
+	assert.Equal(1, w.calls, "WriteHeader should not be called again")
+	assert.Equal("panic: oops", decodeLog(assert, json.NewDecoder(b)).Message, "panic should be logged")
+}
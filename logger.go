@@ -26,9 +26,7 @@ func NewLogger(destinations ...Destination) Logger {
 		validated = append(validated, dest)
 	}
 
-	return &tinyLogger{
-		tags:         make(map[string][]string),
-		destinations: validated}
+	return &tinyLogger{destinations: &destinationSet{list: validated}, stackTraceLevel: noStackTrace}
 }
 
 // Returns new instance of Logger with DefaultDestination.
@@ -37,23 +35,38 @@ func DefaultLogger() Logger {
 }
 
 type fixedLevelLogger struct {
-	l     *tinyLogger
-	level int
+	l          *tinyLogger
+	level      int
+	callerSkip int
 }
 
 func (fll *fixedLevelLogger) Printf(format string, v ...interface{}) {
-	fll.l.Printf(fll.level, format, v...)
+	fll.l.output(fll.level, fmt.Sprintf(format, v...), nil, 1+fll.callerSkip)
 }
 
 func (fll *fixedLevelLogger) Println(v ...interface{}) {
-	fll.l.Println(fll.level, v...)
+	fll.l.output(fll.level, fmt.Sprint(v...), nil, 1+fll.callerSkip)
+}
+
+func (fll *fixedLevelLogger) Printw(message string, keysAndValues ...interface{}) {
+	fll.l.output(fll.level, message, fieldsFromKeysAndValues(keysAndValues), 1+fll.callerSkip)
+}
+
+func (fll *fixedLevelLogger) WithCallerSkip(n int) FixedLevelLogger {
+	return &fixedLevelLogger{l: fll.l, level: fll.level, callerSkip: fll.callerSkip + n}
 }
 
 type tinyLogger struct {
 	mu sync.RWMutex
 
-	tags         map[string][]string
-	destinations []*destination
+	// fields are copied on write, so slice can be used after mu is released.
+	fields          []Field
+	stackTraceLevel int
+	// callerSkip, noCaller and sampler never change after Logger is created.
+	callerSkip   int
+	noCaller     bool
+	sampler      *sampler
+	destinations *destinationSet
 }
 
 func (tl *tinyLogger) SetLogLevel(level int, destinations ...Destination) {
@@ -64,61 +77,262 @@ func (tl *tinyLogger) SetLogLevel(level int, destinations ...Destination) {
 		ids[dest().ID()] = struct{}{}
 	}
 
-	tl.mu.Lock()
+	tl.destinations.mu.Lock()
 
-	for _, dest := range tl.destinations {
+	for _, dest := range tl.destinations.list {
 		if _, ok := ids[dest.ID()]; ok || all {
 			dest.level = level
 		}
 	}
 
-	tl.mu.Unlock()
+	tl.destinations.mu.Unlock()
 }
 
 func (tl *tinyLogger) GetFixedLevel(level int) FixedLevelLogger {
-	return &fixedLevelLogger{tl, level}
+	return &fixedLevelLogger{l: tl, level: level}
 }
 
 func (tl *tinyLogger) AddTag(key string, value ...string) {
 	tl.mu.Lock()
-	tl.tags[key] = append(tl.tags[key], value...)
+
+	var values []string
+	for _, field := range tl.fields {
+		if field.Key == key && field.Kind == formatters.StringsKind {
+			values = append(values, field.Value.([]string)...)
+			break
+		}
+	}
+
+	tl.fields = withFields(tl.fields, Strings(key, append(values, value...)...))
+	tl.mu.Unlock()
+}
+
+func (tl *tinyLogger) AddFields(fields ...Field) {
+	tl.mu.Lock()
+	tl.fields = withFields(tl.fields, fields...)
+	tl.mu.Unlock()
+}
+
+func (tl *tinyLogger) With(fields ...Field) Logger {
+	tl.mu.RLock()
+	defer tl.mu.RUnlock()
+
+	return tl.copy(withFields(tl.fields, fields...), tl.callerSkip, tl.noCaller)
+}
+
+func (tl *tinyLogger) WithCallerSkip(n int) Logger {
+	tl.mu.RLock()
+	defer tl.mu.RUnlock()
+
+	return tl.copy(tl.fields, tl.callerSkip+n, tl.noCaller)
+}
+
+func (tl *tinyLogger) WithoutCaller() Logger {
+	tl.mu.RLock()
+	defer tl.mu.RUnlock()
+
+	return tl.copy(tl.fields, tl.callerSkip, true)
+}
+
+func (tl *tinyLogger) WithSampling(opts SamplingOptions) Logger {
+	tl.mu.RLock()
+	defer tl.mu.RUnlock()
+
+	sampled := tl.copy(tl.fields, tl.callerSkip, tl.noCaller)
+	sampled.sampler = newSampler(opts)
+
+	return sampled
+}
+
+// Returns copy of Logger with fields added, that has own levels of destinations,
+// so changing them does not affect Logger.
+func (tl *tinyLogger) withOwnLevels(fields ...Field) *tinyLogger {
+	tl.mu.RLock()
+	defer tl.mu.RUnlock()
+
+	tl.destinations.mu.RLock()
+
+	list := make([]*destination, len(tl.destinations.list))
+	for i, dest := range tl.destinations.list {
+		clone := *dest
+		list[i] = &clone
+	}
+
+	tl.destinations.mu.RUnlock()
+
+	own := tl.copy(withFields(tl.fields, fields...), tl.callerSkip, tl.noCaller)
+	own.destinations = &destinationSet{list: list}
+
+	return own
+}
+
+// Must be called with tl.mu held.
+func (tl *tinyLogger) copy(fields []Field, callerSkip int, noCaller bool) *tinyLogger {
+	return &tinyLogger{
+		fields:          fields,
+		stackTraceLevel: tl.stackTraceLevel,
+		callerSkip:      callerSkip,
+		noCaller:        noCaller,
+		sampler:         tl.sampler,
+		destinations:    tl.destinations}
+}
+
+func (tl *tinyLogger) SetStackTraceLevel(level int) {
+	tl.mu.Lock()
+	tl.stackTraceLevel = level
 	tl.mu.Unlock()
 }
 
 func (tl *tinyLogger) Printf(level int, format string, v ...interface{}) {
-	tl.output(level, fmt.Sprintf(format, v...), 1)
+	tl.output(level, fmt.Sprintf(format, v...), nil, 1)
 }
 
 func (tl *tinyLogger) Println(level int, v ...interface{}) {
-	tl.output(level, fmt.Sprint(v...), 1)
+	tl.output(level, fmt.Sprint(v...), nil, 1)
+}
+
+func (tl *tinyLogger) Printw(level int, message string, keysAndValues ...interface{}) {
+	tl.output(level, message, fieldsFromKeysAndValues(keysAndValues), 1)
+}
+
+func (tl *tinyLogger) Error(err error, message string, keysAndValues ...interface{}) {
+	tl.output(Error, message, append([]Field{Err(err)}, fieldsFromKeysAndValues(keysAndValues)...), 1)
 }
 
 func (tl *tinyLogger) Fatalf(format string, v ...interface{}) {
-	tl.output(Fatal, fmt.Sprintf(format, v...), 1)
+	tl.output(Fatal, fmt.Sprintf(format, v...), nil, 1)
+	tl.Close()
 	os.Exit(1)
 }
 
 func (tl *tinyLogger) Fatalln(v ...interface{}) {
-	tl.output(Fatal, fmt.Sprint(v...), 1)
+	tl.output(Fatal, fmt.Sprint(v...), nil, 1)
+	tl.Close()
 	os.Exit(1)
 }
 
-func (tl *tinyLogger) output(level int, message string, calldepth int) {
+func (tl *tinyLogger) Fatalw(message string, keysAndValues ...interface{}) {
+	tl.output(Fatal, message, fieldsFromKeysAndValues(keysAndValues), 1)
+	tl.Close()
+	os.Exit(1)
+}
+
+func (tl *tinyLogger) Sync() error {
+	tl.flushSampler()
+
+	tl.destinations.mu.RLock()
+	defer tl.destinations.mu.RUnlock()
+
+	return closeDestinations(tl.destinations.list, false)
+}
+
+func (tl *tinyLogger) Close() error {
+	tl.flushSampler()
+
+	tl.destinations.mu.RLock()
+	defer tl.destinations.mu.RUnlock()
+
+	return closeDestinations(tl.destinations.list, true)
+}
+
+// Writes summaries of entries dropped by Logger sampler.
+func (tl *tinyLogger) flushSampler() {
+	if tl.sampler == nil {
+		return
+	}
+
+	tl.mu.RLock()
+	base := tl.fields
+	tl.mu.RUnlock()
+
+	tl.destinations.mu.RLock()
+	writeSummaries(tl.destinations.list, base, tl.sampler.flush())
+	tl.destinations.mu.RUnlock()
+}
+
+// extra fields are added to entry only, logger fields stay untouched.
+// Caller is not looked up if calldepth is negative.
+func (tl *tinyLogger) output(level int, message string, extra []Field, calldepth int) {
 	tl.mu.RLock()
-	for _, dest := range tl.destinations {
+	base := tl.fields
+	withStack := level >= tl.stackTraceLevel
+	tl.mu.RUnlock()
+
+	fields := base
+	if len(extra) > 0 {
+		fields = withFields(fields, extra...)
+	}
+
+	noCaller := tl.noCaller || calldepth < 0
+	if calldepth < 0 {
+		calldepth = 0
+	}
+
+	calldepth += tl.callerSkip
+	// formatters do not look up caller if calldepth is negative.
+	formatterCalldepth := calldepth + 1
+	if noCaller {
+		formatterCalldepth = -1
+	}
+
+	tl.destinations.mu.RLock()
+	defer tl.destinations.mu.RUnlock()
+
+	if tl.sampler != nil {
+		if !acceptsLevel(tl.destinations.list, level) {
+			return
+		}
+
+		allowed, summaries := tl.sampler.sample(level, message)
+		writeSummaries(tl.destinations.list, base, summaries)
+
+		if !allowed {
+			return
+		}
+	}
+
+	for _, dest := range tl.destinations.list {
 		if dest.level > level {
 			continue
 		}
 
-		bytes := dest.formatter.GetOutput(level, message, tl.tags, calldepth+1)
+		if dest.sampler != nil {
+			allowed, summaries := dest.sampler.sample(level, message)
+			writeSummaries([]*destination{dest}, base, summaries)
+
+			if !allowed {
+				continue
+			}
+		}
+
+		// stack is captured once and only if there is Destination to write it to.
+		if withStack {
+			withStack = false
+			// calldepth is relative to the caller of output.
+			fields = withFields(fields, formatters.Stack(captureStack(calldepth+1)))
+		}
+
+		writeEntry(dest, dest.formatter.GetOutput(level, message, fields, formatterCalldepth))
+	}
+}
 
-		if _, err := dest.out.Write(bytes); err != nil {
-			fmt.Printf(
-				formatters.PaintText(
-					formatters.ANSIColorRed,
-					fmt.Sprintf("failed to write log to destination %s: %s",
-						dest.ID(), err)))
+// Reports if any of destinations accepts level.
+func acceptsLevel(destinations []*destination, level int) bool {
+	for _, dest := range destinations {
+		if dest.level <= level {
+			return true
 		}
 	}
-	tl.mu.RUnlock()
+
+	return false
+}
+
+func writeEntry(dest *destination, bytes []byte) {
+	if _, err := dest.out.Write(bytes); err != nil {
+		fmt.Printf(
+			formatters.PaintText(
+				formatters.ANSIColorRed,
+				fmt.Sprintf("failed to write log to destination %s: %s",
+					dest.ID(), err)))
+	}
 }
@@ -2,7 +2,17 @@ package tinylog
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/andriiyaremenko/tinylog/formatters"
 	"github.com/stretchr/testify/assert"
@@ -13,8 +23,20 @@ func TestLogger(t *testing.T) {
 	t.Run("SetLogLevel changes verbosity level", testSetLogLevel)
 	t.Run("SetLogLevel changes verbosity level for particular Destination", testSetLogLevelForDestination)
 	t.Run("AddTag adds tag to output", testAddTag)
+	t.Run("AddFields adds typed fields to output", testAddFields)
+	t.Run("Printw adds fields to one entry only", testPrintw)
+	t.Run("Printw prints nil pointer error as <nil>", testPrintwNilPointerError)
+	t.Run("Printw prints nil pointer fmt.Stringer as <nil>", testPrintwNilPointerStringer)
+	t.Run("With returns Logger with own fields and shared verbosity levels", testWith)
+	t.Run("Sync flushes and Close closes every Destination once", testSyncClose)
 	t.Run("GetFixedLevel returns FixedLevelLogger of correct level", testGetFixedLevel)
 	t.Run("FixedLevelLogger respects verbosity level", testFixedLevelRespectsVerbosity)
+	t.Run("SetStackTraceLevel adds stack trace to entries of level or higher", testStackTrace)
+	t.Run("Error writes error with its wrapped errors and stack trace", testError)
+	t.Run("Error writes nil pointer error", testErrorNilPointer)
+	t.Run("WithCallerSkip skips frames of helpers", testWithCallerSkip)
+	t.Run("WithoutCaller does not look up caller", testWithoutCaller)
+	t.Run("Fatal functions flush Destinations before exit", testFatalFlushes)
 }
 
 func testDefaultLogLevelIsInfo(t *testing.T) {
@@ -106,6 +128,157 @@ func testAddTag(t *testing.T) {
 	assert.Contains(result, "cat", "tag should be printed")
 }
 
+func testAddFields(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	l := NewLogger(DestinationFunc(b, formatters.JSONFormatter, Info))
+
+	l.AddTag("user", "me")
+	l.AddFields(Int("id", 42), Bool("admin", true))
+	l.AddTag("user", "cat")
+	l.Println(Info, "info")
+
+	m := new(formatters.Log)
+
+	if err := json.Unmarshal(b.Bytes(), m); err != nil {
+		assert.FailNow("got wrong log format")
+	}
+
+	assert.Equal([]interface{}{"me", "cat"}, m.Tags["user"], "tag should be printed")
+	assert.Equal(float64(42), m.Tags["id"], "int field should be printed")
+	assert.Equal(true, m.Tags["admin"], "bool field should be printed")
+}
+
+func testPrintw(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	l := NewLogger(DestinationFunc(b, formatters.JSONFormatter, Info))
+
+	l.AddTag("user", "me")
+	l.Printw(Info, "user created", "id", 42, "plan", "pro", Bool("admin", true), "dangling")
+
+	dec := json.NewDecoder(b)
+	m := new(formatters.Log)
+	if err := dec.Decode(m); err != nil {
+		assert.FailNow("got wrong log format")
+	}
+
+	assert.Equal("user created", m.Message, "message should be printed")
+	assert.Equal([]interface{}{"me"}, m.Tags["user"], "tag should be printed")
+	assert.Equal(float64(42), m.Tags["id"], "field should be printed")
+	assert.Equal("pro", m.Tags["plan"], "field should be printed")
+	assert.Equal(true, m.Tags["admin"], "Field should be printed")
+	assert.Equal("dangling", m.Tags["!BADKEY"], "value without pair should be printed")
+
+	l.GetFixedLevel(Warn).Printw("plan changed", "plan", "free")
+	l.Println(Info, "info")
+
+	m = new(formatters.Log)
+	if err := dec.Decode(m); err != nil {
+		assert.FailNow("got wrong log format")
+	}
+
+	assert.Equal("WARN", m.Level, "FixedLevelLogger level should be used")
+	assert.Equal("free", m.Tags["plan"], "field should be printed")
+
+	m = new(formatters.Log)
+	if err := dec.Decode(m); err != nil {
+		assert.FailNow("got wrong log format")
+	}
+
+	assert.Equal(map[string]interface{}{"user": []interface{}{"me"}}, m.Tags,
+		"fields should not be added to Logger")
+}
+
+func testPrintwNilPointerError(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	l := NewLogger(DestinationFunc(b, formatters.Logfmt, Info))
+
+	assert.NotPanics(func() { l.Printw(Info, "x", "err", (*messageError)(nil)) }, "Printw should not panic")
+	assert.Contains(b.String(), fmt.Sprintf("err=%v", (*messageError)(nil)), "error should be printed as fmt prints it")
+}
+
+func testPrintwNilPointerStringer(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	l := NewLogger(
+		DestinationFunc(b, formatters.Logfmt, Info),
+		DestinationFunc(new(bytes.Buffer), formatters.Default(), Info))
+
+	assert.NotPanics(func() { l.Printw(Info, "x", "t", (*messageStringer)(nil)) }, "Printw should not panic")
+	assert.Contains(b.String(), fmt.Sprintf("t=%v", (*messageStringer)(nil)), "value should be printed as fmt prints it")
+}
+
+func testWith(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	parent := NewLogger(DestinationFunc(b, formatters.JSONFormatter, Info))
+
+	parent.AddTag("user", "me")
+
+	child := parent.With(String("request", "42"))
+
+	child.AddTag("user", "cat")
+	parent.AddFields(Bool("admin", true))
+
+	dec := json.NewDecoder(b)
+
+	child.Println(Info, "child")
+
+	m := new(formatters.Log)
+	if err := dec.Decode(m); err != nil {
+		assert.FailNow("got wrong log format")
+	}
+
+	assert.Equal(map[string]interface{}{
+		"user":    []interface{}{"me", "cat"},
+		"request": "42",
+	}, m.Tags, "child should have parent tags and its own fields")
+
+	parent.Println(Info, "parent")
+
+	m = new(formatters.Log)
+	if err := dec.Decode(m); err != nil {
+		assert.FailNow("got wrong log format")
+	}
+
+	assert.Equal(map[string]interface{}{
+		"user":  []interface{}{"me"},
+		"admin": true,
+	}, m.Tags, "parent should not have child fields")
+
+	child.SetLogLevel(Error)
+	parent.Println(Info, "parent")
+
+	assert.Empty(b.String(), "child and parent should share verbosity levels")
+}
+
+func testSyncClose(t *testing.T) {
+	assert := assert.New(t)
+	cw := new(closableWriter)
+	gw := newGatedWriter()
+	aw := NewAsyncWriter(gw, AsyncOptions{})
+	l := NewLogger(
+		DestinationFunc(cw, formatters.Default(), Info),
+		DestinationFunc(cw, formatters.JSONFormatter, Info),
+		DestinationFunc(aw, formatters.JSONFormatter, Info),
+		DefaultDestination,
+	)
+
+	assert.NoError(l.Sync())
+	assert.Equal(1, cw.flushed, "Destination out should be flushed once")
+	assert.Zero(cw.closed, "Destination out should not be closed by Sync")
+
+	l.Println(Info, "before close")
+	close(gw.gate)
+
+	assert.NoError(l.Close())
+	assert.Equal(2, cw.flushed, "Destination out should be flushed before close")
+	assert.Equal(1, cw.closed, "Destination out should be closed once")
+	assert.Contains(gw.cw.String(), "before close", "queued entries should be written on Close")
+}
+
 func testGetFixedLevel(t *testing.T) {
 	assert := assert.New(t)
 	l, _, b := getLogger()
@@ -150,3 +323,284 @@ func testFixedLevelRespectsVerbosity(t *testing.T) {
 	assert.Contains(result, "me", "tag still should be printed")
 	assert.Contains(result, "cat", "tag still should be printed")
 }
+
+func testStackTrace(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	l := NewLogger(DestinationFunc(b, formatters.JSONFormatter, Info))
+
+	l.Println(Error, "no stack")
+	l.SetStackTraceLevel(Error)
+	l.Println(Warn, "warn")
+	l.Println(Error, "error")
+	l.With(String("user", "me")).GetFixedLevel(Fatal).Println("fatal")
+
+	dec := json.NewDecoder(b)
+	for _, message := range []string{"no stack", "warn"} {
+		m := new(formatters.Log)
+		if err := dec.Decode(m); err != nil {
+			assert.FailNow("got wrong log format")
+		}
+
+		assert.Equal(message, m.Message)
+		assert.Nil(m.Stack, "stack should not be captured")
+	}
+
+	for _, message := range []string{"error", "fatal"} {
+		m := new(formatters.Log)
+		if err := dec.Decode(m); err != nil {
+			assert.FailNow("got wrong log format")
+		}
+
+		assert.Equal(message, m.Message)
+		if assert.NotEmpty(m.Stack, "stack should be captured") {
+			assert.Equal("github.com/andriiyaremenko/tinylog.testStackTrace", m.Stack[0].Function,
+				"stack should start at the caller")
+			assert.True(strings.HasSuffix(m.Stack[0].File, "logger_test.go"), "stack should start at the caller")
+		}
+
+		for _, frame := range m.Stack {
+			assert.False(strings.HasPrefix(frame.Function, "runtime."), "runtime frames should be left out")
+		}
+
+		assert.NotContains(m.Tags, formatters.StackKey, "stack should not be printed as a tag")
+	}
+}
+
+func testErrorNilPointer(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	l := NewLogger(
+		DestinationFunc(b, formatters.JSONFormatter, Info),
+		DestinationFunc(new(bytes.Buffer), formatters.Default(), Info))
+
+	assert.NotPanics(func() { l.Error((*messageError)(nil), "failed") }, "Error should not panic")
+	assert.NotPanics(func() { l.Error(fmt.Errorf("wrapped: %w", (*stackError)(nil)), "failed") },
+		"Error should not panic")
+
+	dec := json.NewDecoder(b)
+	for _, expected := range []*formatters.ErrorInfo{
+		{Message: "<nil>", Type: "*tinylog.messageError"},
+		{Message: "wrapped: with stack", Type: "*fmt.wrapError",
+			Causes: []*formatters.ErrorInfo{{Message: "with stack", Type: "*tinylog.stackError"}}},
+	} {
+		m := new(struct {
+			Tags struct {
+				Error *formatters.ErrorInfo `json:"error"`
+			} `json:"tags"`
+		})
+
+		if err := dec.Decode(m); err != nil {
+			assert.FailNow("got wrong log format")
+		}
+
+		assert.Equal(expected, m.Tags.Error, "nil pointer error should be printed as fmt prints it")
+	}
+}
+
+// messageError has Error method that panics on nil pointer.
+type messageError struct {
+	message string
+}
+
+func (me *messageError) Error() string {
+	return me.message
+}
+
+// messageStringer has String method that panics on nil pointer.
+type messageStringer struct {
+	message string
+}
+
+func (ms *messageStringer) String() string {
+	return ms.message
+}
+
+// stackError carries stack trace the same way github.com/pkg/errors does.
+type stackError struct {
+	pcs []uintptr
+}
+
+type stackFrame uintptr
+
+func newStackError() error {
+	pcs := make([]uintptr, 32)
+	return &stackError{pcs: pcs[:runtime.Callers(1, pcs)]}
+}
+
+func (se *stackError) Error() string {
+	return "with stack"
+}
+
+func (se *stackError) StackTrace() []stackFrame {
+	frames := make([]stackFrame, len(se.pcs))
+	for i, pc := range se.pcs {
+		frames[i] = stackFrame(pc)
+	}
+
+	return frames
+}
+
+func testError(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	l := NewLogger(DestinationFunc(b, formatters.JSONFormatter, Info))
+	err := fmt.Errorf("save user: %w", errors.Join(os.ErrNotExist, newStackError()))
+
+	l.Error(err, "failed", "id", 42)
+
+	m := new(struct {
+		formatters.Log
+		Tags struct {
+			ID    int                   `json:"id"`
+			Error *formatters.ErrorInfo `json:"error"`
+		} `json:"tags"`
+	})
+
+	if err := json.Unmarshal(b.Bytes(), m); err != nil {
+		assert.FailNow("got wrong log format")
+	}
+
+	assert.Equal("ERROR", m.Level, "Error level should be used")
+	assert.Equal("failed", m.Message, "message should be printed")
+	assert.Equal(42, m.Tags.ID, "fields should be printed")
+
+	info := m.Tags.Error
+	if !assert.NotNil(info, "error should be printed") {
+		return
+	}
+
+	assert.Equal(err.Error(), info.Message, "error message should be printed")
+	assert.Equal("*fmt.wrapError", info.Type, "error type should be printed")
+	assert.Equal("*fmt.wrapError > *errors.joinError[*errors.errorString, *tinylog.stackError]", info.TypeChain())
+
+	if !assert.Len(info.Causes, 1) || !assert.Len(info.Causes[0].Causes, 2) {
+		return
+	}
+
+	joined := info.Causes[0]
+	assert.Equal(os.ErrNotExist.Error(), joined.Causes[0].Message, "joined errors should be printed")
+	assert.Empty(joined.Causes[0].Stack, "error without stack trace should not have stack")
+
+	withStack := joined.Causes[1]
+	if assert.NotEmpty(withStack.Stack, "stack trace of error should be printed") {
+		assert.Equal("github.com/andriiyaremenko/tinylog.newStackError", withStack.Stack[0].Function)
+	}
+}
+
+func logFromHelper(l Logger, message string) {
+	l.WithCallerSkip(1).Println(Info, message)
+}
+
+func logFromFixedLevelHelper(l FixedLevelLogger, message string) {
+	l.WithCallerSkip(1).Printw(message)
+}
+
+func testWithCallerSkip(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	l := NewLogger(DestinationFunc(b, formatters.JSONFormatter, Info))
+	l.SetStackTraceLevel(Info)
+
+	_, _, line, _ := runtime.Caller(0)
+	logFromHelper(l, "helper")
+	logFromFixedLevelHelper(l.GetFixedLevel(Warn), "fixed level helper")
+	l.GetFixedLevel(Warn).Println("fixed level")
+
+	dec := json.NewDecoder(b)
+	for i, message := range []string{"helper", "fixed level helper", "fixed level"} {
+		m := decodeLog(assert, dec)
+
+		assert.Equal(message, m.Message)
+		assert.Equal(fmt.Sprintf("logger_test.go:%d", line+i+1), m.Location, "caller of helper should be printed")
+		if assert.NotEmpty(m.Stack) {
+			assert.Equal("github.com/andriiyaremenko/tinylog.testWithCallerSkip", m.Stack[0].Function,
+				"stack should start at caller of helper")
+		}
+	}
+}
+
+func testWithoutCaller(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	l := NewLogger(
+		DestinationFunc(b, formatters.JSONFormatter, Trace),
+		DestinationFunc(b, formatters.NewWithOptions(formatters.Options{Color: formatters.ColorNever}), Trace))
+
+	l.WithoutCaller().Println(Debug, "no caller")
+
+	dec := json.NewDecoder(b)
+	m := decodeLog(assert, dec)
+
+	assert.Equal("no caller", m.Message)
+	assert.Empty(m.Location, "caller should not be printed")
+	assert.Empty(m.Function, "caller should not be printed")
+
+	rest, _ := io.ReadAll(dec.Buffered())
+	assert.Contains(string(rest)+b.String(), "no caller", "message should be printed")
+	assert.NotContains(string(rest)+b.String(), "logger_test.go", "caller should not be printed")
+}
+
+// Set for test binary run by testFatalFlushes.
+const (
+	fatalLogEnv  = "TINYLOG_TEST_FATAL_LOG"
+	fatalFuncEnv = "TINYLOG_TEST_FATAL_FUNC"
+)
+
+// slowWriter delays every Write, so entry is lost if it is not flushed before exit.
+type slowWriter struct {
+	w io.Writer
+}
+
+func (sw *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(50 * time.Millisecond)
+	return sw.w.Write(p)
+}
+
+func testFatalFlushes(t *testing.T) {
+	if path := os.Getenv(fatalLogEnv); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		l := NewLogger(
+			AsyncDestination(DestinationFunc(&slowWriter{w: f}, formatters.JSONFormatter, Info), AsyncOptions{}))
+
+		switch os.Getenv(fatalFuncEnv) {
+		case "Fatalf":
+			l.Fatalf("fatal %s", "Fatalf")
+		case "Fatalln":
+			l.Fatalln("fatal Fatalln")
+		case "Fatalw":
+			l.Fatalw("fatal Fatalw", "id", 42)
+		}
+
+		t.Fatal("Fatal function should exit")
+	}
+
+	assert := assert.New(t)
+
+	for _, fn := range []string{"Fatalf", "Fatalln", "Fatalw"} {
+		path := filepath.Join(t.TempDir(), "fatal.log")
+
+		cmd := exec.Command(os.Args[0], "-test.run=^TestLogger$/^Fatal")
+		cmd.Env = append(os.Environ(), fatalLogEnv+"="+path, fatalFuncEnv+"="+fn)
+
+		var exitErr *exec.ExitError
+		if assert.ErrorAs(cmd.Run(), &exitErr, "%s should exit with error", fn) {
+			assert.Equal(1, exitErr.ExitCode(), "%s should exit with code 1", fn)
+		}
+
+		b, err := os.ReadFile(path)
+		assert.NoError(err)
+
+		m := new(formatters.Log)
+		if err := json.Unmarshal(b, m); err != nil {
+			assert.FailNowf("entry should be written before exit", "%s wrote no entry", fn)
+		}
+
+		assert.Equal("FATAL", m.Level, "Fatal level should be used")
+		assert.Equal("fatal "+fn, m.Message, "entry should be written before exit")
+	}
+}
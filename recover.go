@@ -0,0 +1,59 @@
+package tinylog
+
+import (
+	"fmt"
+
+	"github.com/andriiyaremenko/tinylog/formatters"
+)
+
+// Configuration of Recover and LogPanic.
+type RecoverOptions struct {
+	// Panic is logged with Fatal level instead of Error.
+	// Program does not exit either way.
+	Fatal bool
+	// Recover panics again with recovered value once it is logged.
+	Repanic bool
+}
+
+// Recovers panic, logs it with l and panics again if opts.Repanic is set.
+// Must be called directly by defer: defer tinylog.Recover(l, opts).
+func Recover(l Logger, opts RecoverOptions) {
+	value := recover()
+	if value == nil {
+		return
+	}
+
+	LogPanic(l, value, opts)
+
+	if opts.Repanic {
+		panic(value)
+	}
+}
+
+// Logs recovered panic value with l along with stack trace of the panic
+// and flushes Destinations of l.
+// Entry and stack trace point at the place panic happened if LogPanic is called by deferred function,
+// otherwise at the caller of LogPanic.
+func LogPanic(l Logger, value interface{}, opts RecoverOptions) {
+	level := Error
+	if opts.Fatal {
+		level = Fatal
+	}
+
+	calldepth := panicCalldepth()
+	message := fmt.Sprintf("panic: %v", value)
+	fields := []Field{Any("panic", value), formatters.Stack(captureStack(calldepth))}
+
+	if tl, ok := l.(*tinyLogger); ok {
+		tl.output(level, message, fields, calldepth)
+	} else {
+		l.Printw(level, message, fields[0], fields[1])
+	}
+
+	if err := l.Sync(); err != nil {
+		fmt.Println(
+			formatters.PaintText(
+				formatters.ANSIColorRed,
+				fmt.Sprintf("failed to flush log of recovered panic: %s", err)))
+	}
+}
@@ -0,0 +1,117 @@
+package tinylog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/andriiyaremenko/tinylog/formatters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecover(t *testing.T) {
+	t.Run("Recover logs panic with its stack trace", testRecover)
+	t.Run("Recover logs panic with Fatal level and panics again", testRecoverRepanic)
+	t.Run("Recover does nothing if there is no panic", testRecoverWithoutPanic)
+	t.Run("LogPanic called by deferred function points at place of panic", testLogPanicInDeferredFunction)
+}
+
+var panicLine int
+
+func panicking(value interface{}) {
+	_, _, panicLine, _ = runtime.Caller(0)
+	panic(value)
+}
+
+func recovered(l Logger, opts RecoverOptions, value interface{}) {
+	defer Recover(l, opts)
+
+	panicking(value)
+}
+
+func testRecover(t *testing.T) {
+	assert := assert.New(t)
+	w := new(closableWriter)
+	l := NewLogger(DestinationFunc(w, formatters.JSONFormatter, Info))
+
+	recovered(l, RecoverOptions{}, errors.New("oops"))
+
+	m := new(formatters.Log)
+	if err := json.Unmarshal(w.Bytes(), m); err != nil {
+		assert.FailNow("got wrong log format")
+	}
+
+	assert.Equal("ERROR", m.Level, "panic should be logged with Error level")
+	assert.Equal("panic: oops", m.Message, "panic value should be printed")
+	assert.Equal(map[string]interface{}{"message": "oops", "type": "*errors.errorString"}, m.Tags["panic"],
+		"panic value should be printed")
+	assert.Equal(fmt.Sprintf("recover_test.go:%d", panicLine+1), m.Location, "place of panic should be printed")
+
+	if assert.NotEmpty(m.Stack, "stack trace should be printed") {
+		assert.Equal("github.com/andriiyaremenko/tinylog.panicking", m.Stack[0].Function,
+			"stack trace should start at place of panic")
+		assert.Equal("github.com/andriiyaremenko/tinylog.recovered", m.Stack[1].Function,
+			"stack trace should start at place of panic")
+	}
+
+	assert.Equal(1, w.flushed, "Destinations should be flushed")
+}
+
+func testRecoverRepanic(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	l := NewLogger(DestinationFunc(b, formatters.JSONFormatter, Info))
+
+	assert.PanicsWithValue("oops", func() { recovered(l, RecoverOptions{Fatal: true, Repanic: true}, "oops") },
+		"recovered value should be panicked again")
+
+	m := new(formatters.Log)
+	if err := json.Unmarshal(b.Bytes(), m); err != nil {
+		assert.FailNow("got wrong log format")
+	}
+
+	assert.Equal("FATAL", m.Level, "panic should be logged with Fatal level")
+	assert.Equal("oops", m.Tags["panic"], "panic value should be printed")
+}
+
+func testRecoverWithoutPanic(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	l := NewLogger(DestinationFunc(b, formatters.JSONFormatter, Info))
+
+	assert.NotPanics(func() {
+		defer Recover(l, RecoverOptions{Repanic: true})
+	})
+	assert.Empty(b.String(), "nothing should be logged")
+}
+
+func testLogPanicInDeferredFunction(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	l := NewLogger(DestinationFunc(b, formatters.JSONFormatter, Info))
+
+	func() {
+		defer func() {
+			if value := recover(); value != nil {
+				LogPanic(l, value, RecoverOptions{})
+			}
+		}()
+
+		panicking("oops")
+	}()
+
+	m := new(formatters.Log)
+	if err := json.Unmarshal(b.Bytes(), m); err != nil {
+		assert.FailNow("got wrong log format")
+	}
+
+	assert.Equal(fmt.Sprintf("recover_test.go:%d", panicLine+1), m.Location, "place of panic should be printed")
+
+	if assert.NotEmpty(m.Stack, "stack trace should be printed") {
+		assert.Equal("github.com/andriiyaremenko/tinylog.panicking", m.Stack[0].Function,
+			"stack trace should start at place of panic")
+	}
+}
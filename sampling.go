@@ -0,0 +1,172 @@
+package tinylog
+
+import (
+	"sync"
+	"time"
+)
+
+const defaultSamplingInterval = time.Second
+
+// Configuration of sampling.
+// Entries are counted by level and message within interval:
+// First entries are written, after that every Thereafter-th entry is written and the rest are dropped.
+// Only entries of levels accepted by at least one Destination are counted.
+// Once interval is over, entry with number of dropped entries is written for every level
+// before the next entry that passes through sampling.
+// Sync and Close write entries with number of entries dropped since then.
+// Fatal entries are never dropped.
+type SamplingOptions struct {
+	// Counters are reset every Interval.
+	// 0 = 1 second.
+	Interval time.Duration
+	// Number of entries with the same level and message written every Interval.
+	First int
+	// Every Thereafter-th entry with the same level and message is written after First.
+	// 0 = none are written.
+	Thereafter int
+}
+
+// Wraps wrapped Destination with sampling.
+// Dropped entries are not formatted.
+func SampledDestination(wrapped Destination, opts SamplingOptions) Destination {
+	dest := wrapped()
+	s := newSampler(opts)
+
+	return func() *destination {
+		return &destination{out: dest.out, formatter: dest.formatter, level: dest.level, sampler: s}
+	}
+}
+
+type samplingKey struct {
+	level   int
+	message string
+}
+
+// Number of entries of level dropped within interval.
+type samplingSummary struct {
+	level    int
+	dropped  int
+	interval time.Duration
+}
+
+// Returns fields of sampling summary entry.
+func (ss samplingSummary) fields() []Field {
+	return []Field{Int("sampled", ss.dropped), Duration("interval", ss.interval)}
+}
+
+const samplingSummaryMessage = "log entries were dropped by sampling"
+
+type sampler struct {
+	mu sync.Mutex
+
+	opts    SamplingOptions
+	now     func() time.Time
+	started time.Time
+	counts  map[samplingKey]int
+	dropped map[int]int
+}
+
+func newSampler(opts SamplingOptions) *sampler {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultSamplingInterval
+	}
+
+	return &sampler{opts: opts, now: time.Now, counts: make(map[samplingKey]int), dropped: make(map[int]int)}
+}
+
+// Reports if entry should be written.
+// Returns summaries of previous interval if it is over.
+func (s *sampler) sample(level int, message string) (bool, []samplingSummary) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var summaries []samplingSummary
+
+	if now := s.now(); now.Sub(s.started) >= s.opts.Interval {
+		summaries = s.takeSummaries()
+
+		s.started = now
+		s.counts = make(map[samplingKey]int)
+	}
+
+	if level >= Fatal {
+		return true, summaries
+	}
+
+	key := samplingKey{level: level, message: message}
+	s.counts[key]++
+	n := s.counts[key]
+
+	if n <= s.opts.First || (s.opts.Thereafter > 0 && (n-s.opts.First)%s.opts.Thereafter == 0) {
+		return true, summaries
+	}
+
+	s.dropped[level]++
+
+	return false, summaries
+}
+
+// Returns summaries of entries dropped since counters were reset or summaries were flushed.
+func (s *sampler) flush() []samplingSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.takeSummaries()
+}
+
+// Returns summaries of dropped entries and resets their number.
+// Must be called with mu held.
+func (s *sampler) takeSummaries() []samplingSummary {
+	var summaries []samplingSummary
+
+	for l := Trace; l <= Fatal; l++ {
+		if s.dropped[l] > 0 {
+			summaries = append(summaries, samplingSummary{level: l, dropped: s.dropped[l], interval: s.opts.Interval})
+		}
+	}
+
+	s.dropped = make(map[int]int)
+
+	return summaries
+}
+
+// Writes summaries of entries dropped by samplers of destinations, each sampler only once.
+func flushDestinationSamplers(destinations []*destination) {
+	done := make(map[*sampler]struct{})
+
+	for _, dest := range destinations {
+		if dest.sampler == nil {
+			continue
+		}
+
+		if _, ok := done[dest.sampler]; ok {
+			continue
+		}
+
+		done[dest.sampler] = struct{}{}
+
+		for _, summary := range dest.sampler.flush() {
+			writeSummary(dest, nil, summary)
+		}
+	}
+}
+
+// Writes summaries to destinations that accept their level.
+func writeSummaries(destinations []*destination, fields []Field, summaries []samplingSummary) {
+	for _, summary := range summaries {
+		for _, dest := range destinations {
+			writeSummary(dest, fields, summary)
+		}
+	}
+}
+
+// Writes summary to dest if it accepts summary level.
+// Sampling summary has no caller.
+func writeSummary(dest *destination, fields []Field, summary samplingSummary) {
+	if dest.level > summary.level {
+		return
+	}
+
+	fields = withFields(fields, summary.fields()...)
+	writeEntry(dest, dest.formatter.GetOutput(summary.level, samplingSummaryMessage, fields, -1))
+}
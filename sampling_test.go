@@ -0,0 +1,177 @@
+package tinylog
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/andriiyaremenko/tinylog/formatters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampling(t *testing.T) {
+	t.Run("WithSampling writes first entries and every Thereafter-th after them", testWithSampling)
+	t.Run("WithSampling writes summary of dropped entries once interval is over", testSamplingSummary)
+	t.Run("SampledDestination samples entries of one Destination only", testSampledDestination)
+	t.Run("Entries of levels no Destination accepts are not sampled", testSamplingSkipsFilteredLevels)
+	t.Run("Sync and Close write summaries of dropped entries", testSamplingSummaryOnSync)
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (fc *fakeClock) Now() time.Time {
+	return fc.now
+}
+
+func decodeMessages(assert *assert.Assertions, b *bytes.Buffer) []string {
+	var messages []string
+
+	dec := json.NewDecoder(b)
+	for dec.More() {
+		messages = append(messages, decodeLog(assert, dec).Message)
+	}
+
+	return messages
+}
+
+func testWithSampling(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	l := NewLogger(DestinationFunc(b, formatters.JSONFormatter, Trace)).
+		WithSampling(SamplingOptions{First: 2, Thereafter: 3, Interval: time.Minute})
+
+	for i := 1; i <= 10; i++ {
+		l.Printf(Info, "info")
+		l.Printf(Debug, "info")
+		l.Printf(Info, "other")
+		l.Printf(Fatal, "fatal")
+	}
+
+	messages := decodeMessages(assert, b)
+	count := func(message string) int {
+		n := 0
+		for _, m := range messages {
+			if m == message {
+				n++
+			}
+		}
+
+		return n
+	}
+
+	// entries 1, 2, 5 and 8 are written.
+	assert.Equal(8, count("info"), "entries should be counted by level and message")
+	assert.Equal(4, count("other"), "entries should be counted by level and message")
+	assert.Equal(10, count("fatal"), "Fatal entries should never be dropped")
+}
+
+func testSamplingSummary(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	clock := &fakeClock{now: time.Now()}
+	l := NewLogger(DestinationFunc(b, formatters.JSONFormatter, Info)).
+		WithSampling(SamplingOptions{First: 1, Interval: time.Minute})
+	l.(*tinyLogger).sampler.now = clock.Now
+
+	l.AddTag("user", "me")
+	for i := 0; i < 5; i++ {
+		l.Println(Info, "info")
+		l.Println(Warn, "warn")
+		l.Println(Debug, "debug")
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	l.Println(Info, "info")
+
+	dec := json.NewDecoder(b)
+	for _, message := range []string{"info", "warn"} {
+		assert.Equal(message, decodeLog(assert, dec).Message, "first entries should be written")
+	}
+
+	for _, level := range []string{"INFO", "WARN"} {
+		m := decodeLog(assert, dec)
+
+		assert.Equal(samplingSummaryMessage, m.Message, "summary should be written")
+		assert.Equal(level, m.Level, "summary should be written for every level")
+		assert.Equal(float64(4), m.Tags["sampled"], "number of dropped entries should be printed")
+		assert.Equal("1m0s", m.Tags["interval"], "interval should be printed")
+		assert.Equal([]interface{}{"me"}, m.Tags["user"], "Logger tags should be printed")
+		assert.Empty(m.Location, "summary should have no caller")
+	}
+
+	assert.Equal("info", decodeLog(assert, dec).Message, "counters should be reset")
+	assert.False(dec.More(), "there should be no other entries")
+}
+
+func testSampledDestination(t *testing.T) {
+	assert := assert.New(t)
+	sampled := new(bytes.Buffer)
+	full := new(bytes.Buffer)
+	l := NewLogger(
+		SampledDestination(DestinationFunc(sampled, formatters.JSONFormatter, Info), SamplingOptions{First: 1}),
+		DestinationFunc(full, formatters.JSONFormatter, Info))
+
+	for i := 0; i < 3; i++ {
+		l.Println(Info, "info")
+	}
+
+	assert.Equal([]string{"info"}, decodeMessages(assert, sampled), "entries should be sampled")
+	assert.Equal([]string{"info", "info", "info"}, decodeMessages(assert, full), "entries should not be sampled")
+}
+
+func testSamplingSkipsFilteredLevels(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	l := NewLogger(DestinationFunc(b, formatters.JSONFormatter, Info)).
+		WithSampling(SamplingOptions{First: 1, Interval: time.Minute})
+
+	for i := 0; i < 100; i++ {
+		l.Println(Debug, "debug")
+	}
+
+	l.SetLogLevel(Debug)
+	l.Println(Debug, "debug")
+	assert.NoError(l.Sync())
+
+	assert.Equal([]string{"debug"}, decodeMessages(assert, b), "filtered entries should not be counted as dropped")
+}
+
+func testSamplingSummaryOnSync(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	sampled := new(bytes.Buffer)
+	l := NewLogger(
+		DestinationFunc(b, formatters.JSONFormatter, Info),
+		SampledDestination(DestinationFunc(sampled, formatters.JSONFormatter, Info), SamplingOptions{First: 1}),
+	).WithSampling(SamplingOptions{First: 2, Interval: time.Minute})
+
+	for i := 0; i < 5; i++ {
+		l.Println(Info, "info")
+	}
+
+	assert.NoError(l.Sync())
+	assert.NoError(l.Close())
+
+	type entry struct {
+		message string
+		sampled interface{}
+	}
+
+	for buf, expected := range map[*bytes.Buffer][]entry{
+		b:       {{"info", nil}, {"info", nil}, {samplingSummaryMessage, float64(3)}},
+		sampled: {{"info", nil}, {samplingSummaryMessage, float64(3)}, {samplingSummaryMessage, float64(1)}},
+	} {
+		var entries []entry
+
+		dec := json.NewDecoder(buf)
+		for dec.More() {
+			m := decodeLog(assert, dec)
+			entries = append(entries, entry{m.Message, m.Tags["sampled"]})
+		}
+
+		assert.Equal(expected, entries, "pending summaries should be written once")
+	}
+}
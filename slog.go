@@ -0,0 +1,275 @@
+package tinylog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/andriiyaremenko/tinylog/formatters"
+)
+
+const (
+	// slog.Level mapped to Trace.
+	// Every slog.Level below slog.LevelDebug is mapped to Trace.
+	SlogLevelTrace slog.Level = slog.LevelDebug - 4
+	// slog.Level mapped to Fatal.
+	// Every slog.Level starting from SlogLevelFatal is mapped to Fatal.
+	SlogLevelFatal slog.Level = slog.LevelError + 4
+)
+
+// Returns new instance of SlogHandler based on destinations.
+func NewSlogHandler(destinations ...Destination) *SlogHandler {
+	l := NewLogger(destinations...).(*tinyLogger)
+
+	return &SlogHandler{l: l, extractors: defaultContextExtractors}
+}
+
+// Returns log level (Trace..Fatal) that corresponds to slog.Level.
+func FromSlogLevel(level slog.Level) int {
+	switch {
+	case level < slog.LevelDebug:
+		return Trace
+	case level < slog.LevelInfo:
+		return Debug
+	case level < slog.LevelWarn:
+		return Info
+	case level < slog.LevelError:
+		return Warn
+	case level < SlogLevelFatal:
+		return Error
+	default:
+		return Fatal
+	}
+}
+
+// SlogHandler is slog.Handler that writes records to Destinations using their formatters.
+// Attributes are written as fields, groups are written as nested objects.
+type SlogHandler struct {
+	l          *tinyLogger
+	extractors []ContextExtractor
+	// groups opened with WithGroup, attributes added after group was opened are kept with the group.
+	groups []slogGroup
+}
+
+type slogGroup struct {
+	name  string
+	attrs []slog.Attr
+}
+
+// Sets verbosity level for set of Destinations of SlogHandler and all handlers derived from it.
+func (sh *SlogHandler) SetLogLevel(level int, destinations ...Destination) {
+	sh.l.SetLogLevel(level, destinations...)
+}
+
+// Reports if any of Destinations accepts level.
+func (sh *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	tinyLevel := FromSlogLevel(level)
+
+	sh.l.destinations.mu.RLock()
+	defer sh.l.destinations.mu.RUnlock()
+
+	for _, dest := range sh.l.destinations.list {
+		if dest.level <= tinyLevel {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns new SlogHandler that shares Destinations and their verbosity levels with this SlogHandler,
+// but runs extractors for context of every record instead of TraceExtractor.
+// Include TraceExtractor to keep trace ID and span ID.
+func (sh *SlogHandler) WithContextExtractors(extractors ...ContextExtractor) *SlogHandler {
+	return &SlogHandler{l: sh.l, extractors: extractors, groups: sh.groups}
+}
+
+// Writes record to every Destination that accepts record level with record.Time as time of entry.
+// Fields returned by ContextExtractors for ctx are added to record fields.
+func (sh *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+
+	var fields []Field
+	if len(sh.groups) == 0 {
+		fields = fieldsFromAttrs(attrs)
+	} else {
+		fields = sh.groupField(attrs)
+	}
+
+	fields = append(fields, extractFields(ctx, sh.extractors)...)
+	// formatters do not print time of entry if record.Time is zero.
+	fields = append(fields, formatters.EntryTime(record.Time))
+
+	sh.l.output(FromSlogLevel(record.Level), record.Message, fields, calldepthOf(record.PC))
+
+	return nil
+}
+
+// Returns new SlogHandler that shares Destinations and their verbosity levels with this SlogHandler.
+func (sh *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return sh
+	}
+
+	if len(sh.groups) == 0 {
+		return &SlogHandler{l: sh.l.With(fieldsFromAttrs(attrs)...).(*tinyLogger), extractors: sh.extractors}
+	}
+
+	groups := make([]slogGroup, len(sh.groups))
+	copy(groups, sh.groups)
+
+	last := &groups[len(groups)-1]
+	last.attrs = append(append(make([]slog.Attr, 0, len(last.attrs)+len(attrs)), last.attrs...), attrs...)
+
+	return &SlogHandler{l: sh.l, extractors: sh.extractors, groups: groups}
+}
+
+// Returns new SlogHandler that shares Destinations and their verbosity levels with this SlogHandler.
+// Attributes added later are nested in object named after name.
+func (sh *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return sh
+	}
+
+	groups := make([]slogGroup, len(sh.groups), len(sh.groups)+1)
+	copy(groups, sh.groups)
+
+	return &SlogHandler{l: sh.l, extractors: sh.extractors, groups: append(groups, slogGroup{name: name})}
+}
+
+// Returns Field of outermost group with attributes of all opened groups and attrs nested in it.
+// Returns no fields if groups end up empty.
+func (sh *SlogHandler) groupField(attrs []slog.Attr) []Field {
+	var nested map[string]interface{}
+
+	for i := len(sh.groups) - 1; i >= 0; i-- {
+		group := sh.groups[i]
+		m := attrsToMap(group.attrs)
+
+		if i == len(sh.groups)-1 {
+			for k, v := range attrsToMap(attrs) {
+				m[k] = v
+			}
+		} else if len(nested) > 0 {
+			m[sh.groups[i+1].name] = nested
+		}
+
+		nested = m
+	}
+
+	if len(nested) == 0 {
+		return nil
+	}
+
+	return []Field{Object(sh.groups[0].name, nested)}
+}
+
+func fieldsFromAttrs(attrs []slog.Attr) []Field {
+	fields := make([]Field, 0, len(attrs))
+
+	for _, attr := range attrs {
+		attr.Value = attr.Value.Resolve()
+
+		if attr.Equal(slog.Attr{}) {
+			continue
+		}
+
+		if attr.Value.Kind() == slog.KindGroup && attr.Key == "" {
+			fields = append(fields, fieldsFromAttrs(attr.Value.Group())...)
+			continue
+		}
+
+		fields = append(fields, fieldFromAttr(attr))
+	}
+
+	return fields
+}
+
+func fieldFromAttr(attr slog.Attr) Field {
+	v := attr.Value
+
+	switch v.Kind() {
+	case slog.KindString:
+		return String(attr.Key, v.String())
+	case slog.KindInt64:
+		return Int64(attr.Key, v.Int64())
+	case slog.KindFloat64:
+		return Float64(attr.Key, v.Float64())
+	case slog.KindBool:
+		return Bool(attr.Key, v.Bool())
+	case slog.KindDuration:
+		return Duration(attr.Key, v.Duration())
+	case slog.KindTime:
+		return Time(attr.Key, v.Time())
+	case slog.KindGroup:
+		return Object(attr.Key, attrsToMap(v.Group()))
+	default:
+		return Any(attr.Key, v.Any())
+	}
+}
+
+func attrsToMap(attrs []slog.Attr) map[string]interface{} {
+	m := make(map[string]interface{}, len(attrs))
+
+	for _, attr := range attrs {
+		attr.Value = attr.Value.Resolve()
+
+		if attr.Equal(slog.Attr{}) {
+			continue
+		}
+
+		switch attr.Value.Kind() {
+		case slog.KindGroup:
+			group := attrsToMap(attr.Value.Group())
+
+			if attr.Key == "" {
+				for k, v := range group {
+					m[k] = v
+				}
+
+				continue
+			}
+
+			if len(group) > 0 {
+				m[attr.Key] = group
+			}
+		case slog.KindDuration:
+			m[attr.Key] = attr.Value.Duration().String()
+		case slog.KindTime:
+			m[attr.Key] = attr.Value.Time().Format(time.RFC3339Nano)
+		default:
+			if err, ok := attr.Value.Any().(error); ok {
+				m[attr.Key] = formatters.NewErrorInfo(err)
+				continue
+			}
+
+			m[attr.Key] = attr.Value.Any()
+		}
+	}
+
+	return m
+}
+
+// Returns calldepth of the frame with pc relative to the caller of calldepthOf.
+// Returns -1 if record has no pc or frame was not found, e.g. record is handled on another goroutine.
+func calldepthOf(pc uintptr) int {
+	if pc == 0 {
+		return -1
+	}
+
+	var pcs [64]uintptr
+	n := runtime.Callers(2, pcs[:])
+
+	for i, callerPC := range pcs[:n] {
+		if callerPC == pc {
+			return i
+		}
+	}
+
+	return -1
+}
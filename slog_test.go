@@ -0,0 +1,172 @@
+package tinylog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	"github.com/andriiyaremenko/tinylog/formatters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogHandler(t *testing.T) {
+	t.Run("SlogHandler maps slog levels onto log levels", testSlogHandlerLevels)
+	t.Run("SlogHandler Enabled respects Destination log level", testSlogHandlerEnabled)
+	t.Run("SlogHandler writes attributes as fields", testSlogHandlerAttrs)
+	t.Run("SlogHandler passes testing/slogtest", testSlogHandlerSlogtest)
+	t.Run("SlogHandler writes location of slog.Logger caller", testSlogHandlerLocation)
+	t.Run("SlogHandler writes no location for record without PC", testSlogHandlerNoPC)
+}
+
+func getSlogLogger() (*slog.Logger, *SlogHandler, *json.Decoder) {
+	b := new(bytes.Buffer)
+	h := NewSlogHandler(DestinationFunc(b, formatters.JSONFormatter, Trace))
+
+	return slog.New(h), h, json.NewDecoder(b)
+}
+
+func decodeLog(assert *assert.Assertions, dec *json.Decoder) *formatters.Log {
+	m := new(formatters.Log)
+
+	if err := dec.Decode(m); err != nil {
+		assert.FailNow("got wrong log format")
+	}
+
+	return m
+}
+
+func testSlogHandlerLevels(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(Trace, FromSlogLevel(SlogLevelTrace))
+	assert.Equal(Debug, FromSlogLevel(slog.LevelDebug))
+	assert.Equal(Info, FromSlogLevel(slog.LevelInfo))
+	assert.Equal(Warn, FromSlogLevel(slog.LevelWarn))
+	assert.Equal(Error, FromSlogLevel(slog.LevelError))
+	assert.Equal(Fatal, FromSlogLevel(SlogLevelFatal))
+
+	l, _, dec := getSlogLogger()
+
+	l.Warn("warn")
+
+	m := decodeLog(assert, dec)
+
+	assert.Equal("WARN", m.Level, "level should be mapped")
+	assert.Equal("warn", m.Message, "message should be printed")
+}
+
+func testSlogHandlerEnabled(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	h := NewSlogHandler(DestinationFunc(b, formatters.JSONFormatter, Info))
+	l := slog.New(h)
+	ctx := context.TODO()
+
+	assert.False(h.Enabled(ctx, slog.LevelDebug), "Debug should be disabled")
+	assert.True(h.Enabled(ctx, slog.LevelInfo), "Info should be enabled")
+
+	l.Debug("debug")
+
+	assert.Empty(b.String(), "nothing should be printed")
+
+	h.SetLogLevel(Debug)
+
+	assert.True(h.WithGroup("g").Enabled(ctx, slog.LevelDebug),
+		"derived handler should share log level")
+}
+
+func testSlogHandlerAttrs(t *testing.T) {
+	assert := assert.New(t)
+	l, _, dec := getSlogLogger()
+
+	l.With("user", "me").Info("user created", "id", 42, "admin", true, "err", errors.New("oops"))
+
+	m := decodeLog(assert, dec)
+
+	assert.Equal(map[string]interface{}{
+		"user":  "me",
+		"id":    float64(42),
+		"admin": true,
+		"err":   map[string]interface{}{"message": "oops", "type": "*errors.errorString"},
+	}, m.Tags, "attributes should be printed as fields")
+
+	l.WithGroup("request").Info("failed", "err", errors.New("oops"))
+
+	m = decodeLog(assert, dec)
+
+	assert.Equal(map[string]interface{}{
+		"request": map[string]interface{}{
+			"err": map[string]interface{}{"message": "oops", "type": "*errors.errorString"},
+		},
+	}, m.Tags, "errors in groups should be printed the same way")
+}
+
+func testSlogHandlerSlogtest(t *testing.T) {
+	b := new(bytes.Buffer)
+	h := NewSlogHandler(DestinationFunc(b, formatters.JSONFormatter, Trace))
+
+	// results are converted to keys slogtest expects: tags are lifted to the top level.
+	results := func() []map[string]interface{} {
+		var ms []map[string]interface{}
+
+		dec := json.NewDecoder(b)
+		for dec.More() {
+			m := make(map[string]interface{})
+			if err := dec.Decode(&m); err != nil {
+				t.Fatal(err)
+			}
+
+			result, _ := m["tags"].(map[string]interface{})
+			if result == nil {
+				result = make(map[string]interface{})
+			}
+
+			result[slog.LevelKey] = m["level"]
+			result[slog.MessageKey] = m["message"]
+			if date, ok := m["date"]; ok {
+				result[slog.TimeKey] = date
+			}
+
+			ms = append(ms, result)
+		}
+
+		return ms
+	}
+
+	if err := slogtest.TestHandler(h, results); err != nil {
+		t.Error(err)
+	}
+}
+
+func testSlogHandlerLocation(t *testing.T) {
+	assert := assert.New(t)
+	l, _, dec := getSlogLogger()
+
+	l.Info("location")
+	_, _, line, _ := runtime.Caller(0)
+
+	m := decodeLog(assert, dec)
+
+	assert.Equal(fmt.Sprintf("slog_test.go:%d", line-1), m.Location,
+		"location of slog.Logger caller should be printed")
+}
+
+func testSlogHandlerNoPC(t *testing.T) {
+	assert := assert.New(t)
+	_, h, dec := getSlogLogger()
+
+	assert.NoError(h.Handle(context.TODO(), slog.NewRecord(time.Now(), slog.LevelInfo, "no pc", 0)))
+
+	m := decodeLog(assert, dec)
+
+	assert.Equal("no pc", m.Message, "message should be printed")
+	assert.Empty(m.Location, "location should not be printed")
+	assert.Empty(m.Function, "function should not be printed")
+}
@@ -0,0 +1,84 @@
+package tinylog
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/andriiyaremenko/tinylog/formatters"
+)
+
+// Stack traces are not captured for entries of level greater than Fatal.
+const noStackTrace int = Fatal + 1
+
+var packagePath = reflect.TypeOf(tinyLogger{}).PkgPath()
+
+// Returns stack trace starting at frame skip relative to the caller of captureStack.
+// Frames of runtime and tinylog packages are left out.
+func captureStack(skip int) []formatters.Frame {
+	var pcs [64]uintptr
+	// skip runtime.Callers and captureStack.
+	n := runtime.Callers(skip+2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var stack []formatters.Frame
+	for {
+		frame, more := frames.Next()
+
+		if !isInternalFrame(frame) {
+			stack = append(stack, formatters.Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		}
+
+		if !more {
+			return stack
+		}
+	}
+}
+
+// Reports if frame belongs to runtime or tinylog packages (tests excluded).
+func isInternalFrame(frame runtime.Frame) bool {
+	if strings.HasPrefix(frame.Function, "runtime.") {
+		return true
+	}
+
+	if strings.HasSuffix(frame.File, "_test.go") {
+		return false
+	}
+
+	return strings.HasPrefix(frame.Function, packagePath+".") || strings.HasPrefix(frame.Function, packagePath+"/")
+}
+
+// Returns calldepth of the frame panic happened in relative to the caller of panicCalldepth.
+// Returns calldepth of the first frame outside of runtime and tinylog packages if there is no panic in progress.
+func panicCalldepth() int {
+	var pcs [64]uintptr
+	// skip runtime.Callers and panicCalldepth.
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	external := -1
+	panicking := false
+
+	for depth := 0; ; depth++ {
+		frame, more := frames.Next()
+
+		switch {
+		case frame.Function == "runtime.gopanic":
+			panicking = true
+		case panicking && !strings.HasPrefix(frame.Function, "runtime."):
+			return depth
+		case external < 0 && !isInternalFrame(frame):
+			external = depth
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	if external < 0 {
+		return 0
+	}
+
+	return external
+}
@@ -0,0 +1,121 @@
+package tinylog
+
+import (
+	"bytes"
+	"log"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+var regexpLevelPrefix = regexp.MustCompile(`^\s*(?:\[(\w+)\]|(\w+):)\s*`)
+
+var levelPrefixes = map[string]int{
+	"trace":   Trace,
+	"debug":   Debug,
+	"info":    Info,
+	"warn":    Warn,
+	"warning": Warn,
+	"error":   Error,
+	"err":     Error,
+	"fatal":   Fatal,
+}
+
+// Returns *log.Logger that writes every line it gets to l with level.
+func NewStdLogger(l Logger, level int) *log.Logger {
+	return log.New(&stdLogWriter{l: l, level: level}, "", 0)
+}
+
+// Returns *log.Logger that writes every line it gets to l
+// with level found in line prefix like "[WARN]" or "warn:" or with fallbackLevel if there is none.
+// Level prefix is removed from message.
+func NewStdLoggerWithLevelPrefix(l Logger, fallbackLevel int) *log.Logger {
+	return log.New(&stdLogWriter{l: l, level: fallbackLevel, sniffLevel: true}, "", 0)
+}
+
+// Redirects output of standard logger (log.Default()) to l with level.
+// Returns function that restores previous output, prefix and flags of standard logger.
+func RedirectStdLog(l Logger, level int) func() {
+	std := log.Default()
+	out, prefix, flags := std.Writer(), std.Prefix(), std.Flags()
+
+	std.SetOutput(&stdLogWriter{l: l, level: level})
+	std.SetPrefix("")
+	std.SetFlags(0)
+
+	return func() {
+		std.SetOutput(out)
+		std.SetPrefix(prefix)
+		std.SetFlags(flags)
+	}
+}
+
+type stdLogWriter struct {
+	l          Logger
+	level      int
+	sniffLevel bool
+}
+
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	calldepth := callerOutsideStdLog()
+
+	for _, line := range bytes.Split(p, []byte{'\n'}) {
+		message := string(bytes.TrimRight(line, "\r"))
+		if strings.TrimSpace(message) == "" {
+			continue
+		}
+
+		level := w.level
+		if w.sniffLevel {
+			level, message = levelFromPrefix(message, level)
+		}
+
+		if tl, ok := w.l.(*tinyLogger); ok {
+			tl.output(level, message, nil, calldepth)
+			continue
+		}
+
+		w.l.Println(level, message)
+	}
+
+	return len(p), nil
+}
+
+// Returns calldepth relative to stdLogWriter.Write of the first frame outside of log package.
+func callerOutsideStdLog() int {
+	var pcs [32]uintptr
+	// skip runtime.Callers, callerOutsideStdLog and stdLogWriter.Write.
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for depth := 1; ; depth++ {
+		frame, more := frames.Next()
+
+		if !strings.HasPrefix(frame.Function, "log.") {
+			return depth
+		}
+
+		if !more {
+			return depth
+		}
+	}
+}
+
+func levelFromPrefix(message string, fallbackLevel int) (int, string) {
+	match := regexpLevelPrefix.FindStringSubmatch(message)
+	if match == nil {
+		return fallbackLevel, message
+	}
+
+	name := match[1]
+	if name == "" {
+		name = match[2]
+	}
+
+	level, ok := levelPrefixes[strings.ToLower(name)]
+	if !ok {
+		return fallbackLevel, message
+	}
+
+	return level, message[len(match[0]):]
+}
@@ -0,0 +1,84 @@
+package tinylog
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"testing"
+
+	"github.com/andriiyaremenko/tinylog/formatters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStdLogger(t *testing.T) {
+	t.Run("NewStdLogger writes every line with level", testStdLogger)
+	t.Run("NewStdLoggerWithLevelPrefix finds level in line prefix", testStdLoggerWithLevelPrefix)
+	t.Run("RedirectStdLog writes standard logger output to Logger", testRedirectStdLog)
+}
+
+func testStdLogger(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	l := NewLogger(DestinationFunc(b, formatters.JSONFormatter, Info))
+	std := NewStdLogger(l, Warn)
+
+	std.Printf("first line\nsecond line\n")
+
+	dec := json.NewDecoder(b)
+	m := decodeLog(assert, dec)
+
+	assert.Equal("WARN", m.Level, "level should be used")
+	assert.Equal("first line", m.Message, "every line should be printed")
+	assert.Equal("stdlog_test.go:25", m.Location, "location of *log.Logger caller should be printed")
+
+	m = decodeLog(assert, dec)
+
+	assert.Equal("second line", m.Message, "every line should be printed")
+	assert.False(dec.More(), "empty lines should be skipped")
+}
+
+func testStdLoggerWithLevelPrefix(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	l := NewLogger(DestinationFunc(b, formatters.JSONFormatter, Trace))
+	std := NewStdLoggerWithLevelPrefix(l, Info)
+	dec := json.NewDecoder(b)
+
+	for _, tc := range []struct {
+		line    string
+		level   string
+		message string
+	}{
+		{"[WARN] disk is almost full", "WARN", "disk is almost full"},
+		{"error: connection refused", "ERROR", "connection refused"},
+		{"[debug]cache miss", "DEBUG", "cache miss"},
+		{"http: TLS handshake failed", "INFO", "http: TLS handshake failed"},
+		{"just a message", "INFO", "just a message"},
+	} {
+		std.Println(tc.line)
+
+		m := decodeLog(assert, dec)
+
+		assert.Equalf(tc.level, m.Level, "level should be found for %q", tc.line)
+		assert.Equalf(tc.message, m.Message, "prefix should be removed for %q", tc.line)
+	}
+}
+
+func testRedirectStdLog(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	l := NewLogger(DestinationFunc(b, formatters.JSONFormatter, Info))
+	restore := RedirectStdLog(l, Error)
+
+	log.Print("standard")
+	restore()
+
+	m := decodeLog(assert, json.NewDecoder(b))
+
+	assert.Equal("ERROR", m.Level, "level should be used")
+	assert.Equal("standard", m.Message, "message should be printed")
+	assert.Equal("stdlog_test.go:73", m.Location, "location of log.Print caller should be printed")
+	_, redirected := log.Writer().(*stdLogWriter)
+
+	assert.False(redirected, "standard logger output should be restored")
+}
@@ -81,6 +81,15 @@ type LogLevelSetter interface {
 	SetLogLevel(level int, destinations ...Destination)
 }
 
+// Flushes and closes Destinations.
+type SyncCloser interface {
+	// Flushes every Destination out that implements Flusher.
+	Sync() error
+	// Flushes every Destination out that implements Flusher and closes every one that implements io.Closer.
+	// Every out is closed once, os.Stdout and os.Stderr are never closed.
+	Close() error
+}
+
 // Logger bound to concrete log level.
 type FixedLevelLogger interface {
 	// Printf formats according to a format specifier and writes to io.Writer with appended newline.
@@ -88,16 +97,48 @@ type FixedLevelLogger interface {
 	// Fprintln formats using the default formats for its operands and writes to io.Writer.
 	// Spaces are always added between operands and a newline is appended.
 	Println(v ...interface{})
+	// Printw writes message to io.Writer with fields built from alternating keys and values.
+	// Fields are added to this entry only.
+	Printw(message string, keysAndValues ...interface{})
+	// Returns FixedLevelLogger that skips n more stack frames when looking up caller.
+	// Useful for helpers that wrap FixedLevelLogger.
+	WithCallerSkip(n int) FixedLevelLogger
 }
 
 // Logger can print log of different verbosity level.
 type Logger interface {
 	LogLevelSetter
+	SyncCloser
 
 	// Returns instance of FixedLevelLogger that shares tags with Logger instance.
 	GetFixedLevel(level int) FixedLevelLogger
 	// Adds tag to a logger and all instances of FixedLevelLogger created from this Logger.
+	// Values are appended to the tag with the same key.
 	AddTag(key string, value ...string)
+	// Adds typed fields to a logger and all instances of FixedLevelLogger created from this Logger.
+	// Field replaces previously added Field or tag with the same key.
+	AddFields(fields ...Field)
+	// Returns new Logger that shares Destinations and their verbosity levels with this Logger,
+	// but has its own copy of tags and fields with fields added.
+	// Tags and fields added to either of Loggers afterwards are not visible to the other.
+	With(fields ...Field) Logger
+	// Returns new Logger that skips n more stack frames when looking up caller and capturing stack trace,
+	// so entries point at the caller of a helper that wraps Logger instead of the helper itself.
+	// Tags and fields are copied as with With.
+	WithCallerSkip(n int) Logger
+	// Returns new Logger that does not look up caller, so entries have no file and line.
+	// Useful for hot paths where caller is not needed.
+	// Tags and fields are copied as with With.
+	WithoutCaller() Logger
+	// Returns new Logger that samples entries according to opts before they reach Destinations.
+	// Loggers created from it with With, WithCallerSkip or WithoutCaller share its sampling counters.
+	// Tags and fields are copied as with With.
+	WithSampling(opts SamplingOptions) Logger
+	// Makes Logger capture stack trace of the caller for entries of level or higher.
+	// Stack trace leaves out frames of runtime and tinylog packages.
+	// Level greater than Fatal disables stack traces, which is default.
+	// Loggers created with With inherit this setting.
+	SetStackTraceLevel(level int)
 
 	// Printf formats according to a format specifier and writes to io.Writer with level of verbosity.
 	// 0 = Trace;
@@ -116,18 +157,63 @@ type Logger interface {
 	// 4 = Error;
 	// 5 = Fatal;
 	Println(level int, v ...interface{})
-	// Fatalf is equivalent to l.Printf(tinylog.Fatal) followed by a call to os.Exit(1).
+	// Printw writes message to io.Writer with level of verbosity
+	// and fields built from alternating keys and values (e.g. "id", 42, "plan", "pro").
+	// Field can be passed in place of a key.
+	// Fields are added to this entry only, Logger tags and fields stay untouched.
+	// 0 = Trace;
+	// 1 = Debug;
+	// 2 = Info;
+	// 3 = Warn;
+	// 4 = Error;
+	// 5 = Fatal;
+	Printw(level int, message string, keysAndValues ...interface{})
+	// Error writes message to io.Writer with Error level of verbosity, err as "error" field
+	// and fields built from alternating keys and values as in Printw.
+	// JSONFormatter writes err as formatters.ErrorInfo with its type, wrapped errors and stack trace.
+	Error(err error, message string, keysAndValues ...interface{})
+	// Fatalf is equivalent to l.Printf(tinylog.Fatal) followed by a call to l.Close() and os.Exit(1).
 	Fatalf(format string, v ...interface{})
-	// Fatalln is equivalent to l.Println(tinylog.Fatal) followed by a call to os.Exit(1).
+	// Fatalln is equivalent to l.Println(tinylog.Fatal) followed by a call to l.Close() and os.Exit(1).
 	Fatalln(v ...interface{})
+	// Fatalw is equivalent to l.Printw(tinylog.Fatal) followed by a call to l.Close() and os.Exit(1).
+	Fatalw(message string, keysAndValues ...interface{})
 }
 
 // LoggerFactory manages Loggers instances verbosity levels and can get Logger instance bound to context.
+// SetLogLevel of LoggerFactory applies to Loggers with its name and names nested in it,
+// including Loggers that will be created later.
 type LoggerFactory interface {
 	LogLevelSetter
+	// Sync and Close apply to LoggerFactory Destinations and Destinations of every Logger it manages.
+	SyncCloser
 	// Returns instance of Logger bound to provided ctx with listed Destinations.
-	// If no Destination were provided default LoggerFactory Destinations are expected to be used.
+	// If no Destination were provided and there is no Logger bound to ctx yet,
+	// Logger carried by ctx (see NewContext) is used, otherwise default LoggerFactory Destinations are expected to be used.
+	// Logger is tagged with LoggerFactory name if it has one
+	// and with fields ContextExtractors of LoggerFactory return for ctx.
 	GetLogger(ctx context.Context, destinations ...Destination) Logger
+	// Returns LoggerFactory that shares Loggers registry and Destinations with this LoggerFactory,
+	// but gets Loggers named after name nested into this LoggerFactory name with dot: "db" -> "db.pool".
+	Named(name string) LoggerFactory
 	// Returns all Destinations for this LoggerFactory.
 	Destinations() []Destination
+	// Removes Loggers bound to ctx under every name, so next GetLogger with ctx returns new Logger.
+	// Loggers are removed automatically when ctx is done,
+	// Release is needed for contexts that are never cancelled.
+	Release(ctx context.Context)
+	// Returns statistics of Loggers registry shared with Named LoggerFactories.
+	Stats() FactoryStats
+}
+
+// Statistics of LoggerFactory Loggers registry.
+type FactoryStats struct {
+	// Number of Loggers bound to contexts.
+	LiveLoggers int
+	// Number of contexts with Loggers bound to them.
+	LiveContexts int
+	// Total number of Loggers bound to contexts.
+	Created uint64
+	// Total number of Loggers removed by Release or because their context was done.
+	Released uint64
 }
@@ -0,0 +1,23 @@
+package tinylog
+
+import (
+	"context"
+
+	"github.com/andriiyaremenko/tinylog/formatters"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ContextExtractor that returns trace_id, span_id and trace_flags fields of OpenTelemetry span carried by ctx.
+// Returns nil if ctx carries no valid span context.
+func TraceExtractor(ctx context.Context) []Field {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+
+	return []Field{
+		String(formatters.TraceIDKey, sc.TraceID().String()),
+		String(formatters.SpanIDKey, sc.SpanID().String()),
+		String(formatters.TraceFlagsKey, sc.TraceFlags().String()),
+	}
+}
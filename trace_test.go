@@ -0,0 +1,83 @@
+package tinylog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/andriiyaremenko/tinylog/formatters"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTrace(t *testing.T) {
+	t.Run("GetLogger adds trace ID and span ID of span carried by context", testGetLoggerTrace)
+	t.Run("GetLogger does not add trace fields if there is no span", testGetLoggerWithoutTrace)
+	t.Run("SlogHandler adds trace ID and span ID of span carried by context", testSlogHandlerTrace)
+}
+
+func spanContext() context.Context {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	return trace.ContextWithSpanContext(context.TODO(), sc)
+}
+
+func testGetLoggerTrace(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	lf := NewLoggerFactory(
+		DestinationFunc(b, formatters.JSONFormatter, Info),
+		DestinationFunc(b, formatters.NewWithOptions(formatters.Options{Color: formatters.ColorNever}), Info))
+
+	lf.GetLogger(spanContext()).Println(Info, "traced")
+
+	dec := json.NewDecoder(b)
+	m := decodeLog(assert, dec)
+
+	assert.Equal("4bf92f3577b34da6a3ce929d0e0e4736", m.TraceID, "trace ID should be printed")
+	assert.Equal("00f067aa0ba902b7", m.SpanID, "span ID should be printed")
+	assert.Equal("01", m.TraceFlags, "trace flags should be printed")
+	assert.Empty(m.Tags, "trace fields should not be printed as tags")
+
+	b.Reset()
+	lf.GetLogger(spanContext()).Println(Info, "traced")
+
+	s := b.String()
+	assert.Contains(s, "trace_id=4bf92f3577b34da6a3ce929d0e0e4736", "trace ID should be printed")
+	assert.Contains(s, "span_id=00f067aa0ba902b7", "span ID should be printed")
+}
+
+func testGetLoggerWithoutTrace(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	lf := NewLoggerFactory(DestinationFunc(b, formatters.JSONFormatter, Info))
+
+	lf.GetLogger(context.TODO()).Println(Info, "not traced")
+
+	m := decodeLog(assert, json.NewDecoder(b))
+
+	assert.Empty(m.TraceID, "trace ID should not be printed")
+	assert.Empty(m.SpanID, "span ID should not be printed")
+}
+
+func testSlogHandlerTrace(t *testing.T) {
+	assert := assert.New(t)
+	b := new(bytes.Buffer)
+	l := slog.New(NewSlogHandler(DestinationFunc(b, formatters.JSONFormatter, Info)))
+
+	l.InfoContext(spanContext(), "traced", "id", 42)
+
+	m := decodeLog(assert, json.NewDecoder(b))
+
+	assert.Equal("4bf92f3577b34da6a3ce929d0e0e4736", m.TraceID, "trace ID should be printed")
+	assert.Equal("00f067aa0ba902b7", m.SpanID, "span ID should be printed")
+	assert.Equal(map[string]interface{}{"id": float64(42)}, m.Tags, "attributes should be printed")
+}
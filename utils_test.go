@@ -39,3 +39,21 @@ func (cw *concurrentWriter) String() string {
 	defer cw.mu.Unlock()
 	return cw.b.String()
 }
+
+// closableWriter counts calls to Flush and Close.
+type closableWriter struct {
+	bytes.Buffer
+
+	flushed int
+	closed  int
+}
+
+func (cw *closableWriter) Flush() error {
+	cw.flushed++
+	return nil
+}
+
+func (cw *closableWriter) Close() error {
+	cw.closed++
+	return nil
+}